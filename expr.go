@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"math/big"
+	"strconv"
 )
 
 type Expr interface {
@@ -20,6 +21,18 @@ func (n Num) String() string {
 	return n.Rat.RatString()
 }
 
+type Str string
+
+func (s Str) String() string {
+	return strconv.Quote(string(s))
+}
+
+type Bool bool
+
+func (b Bool) String() string {
+	return strconv.FormatBool(bool(b))
+}
+
 type Ident string
 
 func (i Ident) String() string {
@@ -0,0 +1,246 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"sort"
+)
+
+// sparseEntry is a non-zero entry of a sparse matrix row.
+type sparseEntry struct {
+	col int
+	c   Coeff
+}
+
+// sparseRow is a row of a sparse matrix. The entries are sorted by their
+// column, the first entry is the pivot of the row.
+type sparseRow []sparseEntry
+
+// macaulayMatrix is a sparse matrix whose columns are labelled with terms
+// in descending order, so that every row represents a polynomial.
+type macaulayMatrix struct {
+	proto *Polynomial
+	terms []Term
+	cols  map[string]int
+	rows  []sparseRow
+}
+
+// GroebnerF4 calculates the reduced Gröbner basis of the ideal generated
+// by fns using Faugère's F4 algorithm. All critical pairs of minimal degree
+// are reduced simultaneously: the multiples of the basis elements needed
+// for the reduction are collected by a symbolic preprocessing step and the
+// resulting Macaulay matrix is reduced by sparse gaussian elimination.
+func GroebnerF4(fns []*Polynomial) []*Polynomial {
+	var (
+		basis []*Polynomial
+		pairs []critPair
+	)
+	add := func(f *Polynomial) {
+		basis = append(basis, f.Monic())
+		k := len(basis) - 1
+		for i := 0; i < k; i++ {
+			pairs = append(pairs, critPair{i, k,
+				termLCM(basis[i].items[0].T, basis[k].items[0].T)})
+		}
+	}
+	for _, f := range fns {
+		if !f.IsZero() {
+			add(f)
+		}
+	}
+	for len(pairs) > 0 {
+		// select all pairs of minimal degree (normal strategy)
+		deg := pairs[0].lcm.Degree()
+		for _, pair := range pairs[1:] {
+			if d := pair.lcm.Degree(); d.Cmp(deg) < 0 {
+				deg = d
+			}
+		}
+		var selected, rest []critPair
+		for _, pair := range pairs {
+			if pair.lcm.Degree().Cmp(deg) == 0 {
+				selected = append(selected, pair)
+			} else {
+				rest = append(rest, pair)
+			}
+		}
+		pairs = rest
+
+		var rows []*Polynomial
+		for i, pair := range selected {
+			if skipPair(basis, append(pairs, selected[i+1:]...), pair) {
+				continue
+			}
+			for _, k := range []int{pair.i, pair.j} {
+				g := basis[k]
+				rows = append(rows, g.MulMonomial(g.field.One(),
+					termDiv(pair.lcm, g.items[0].T)))
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		for _, f := range reduceF4(rows, basis) {
+			add(f)
+		}
+	}
+	return reduceBasis(basis)
+}
+
+// reduceF4 reduces the polynomials in rows simultaneously modulo basis and
+// returns all non-zero results whose leading terms are new.
+func reduceF4(rows, basis []*Polynomial) []*Polynomial {
+	// symbolic preprocessing: add a multiple of a basis element for every
+	// reducible term that is not already the leading term of a row
+	done := make(map[string]bool)
+	for _, r := range rows {
+		done[termKey(r.items[0].T)] = true
+	}
+	numPairRows := len(rows)
+	for i := 0; i < len(rows); i++ {
+		for _, m := range rows[i].items {
+			key := termKey(m.T)
+			if done[key] {
+				continue
+			}
+			done[key] = true
+			for _, g := range basis {
+				if termDivides(g.items[0].T, m.T) {
+					rows = append(rows, g.MulMonomial(g.field.One(),
+						termDiv(m.T, g.items[0].T)))
+					break
+				}
+			}
+		}
+	}
+
+	mat := newMacaulayMatrix(rows)
+	leads := make(map[int]bool)
+	for _, row := range mat.rows {
+		leads[row[0].col] = true
+	}
+
+	// the reducers have distinct pivots and are used as they are, the rows
+	// of the critical pairs are reduced by all previous pivot rows
+	pivots := make(map[int]sparseRow)
+	for _, row := range mat.rows[numPairRows:] {
+		pivots[row[0].col] = row
+	}
+	var rval []*Polynomial
+	for _, row := range mat.rows[:numPairRows] {
+		row = mat.reduce(row, pivots)
+		if len(row) == 0 {
+			continue
+		}
+		row = mat.monic(row)
+		pivots[row[0].col] = row
+		if !leads[row[0].col] {
+			rval = append(rval, mat.polynomial(row))
+		}
+	}
+	return rval
+}
+
+func newMacaulayMatrix(rows []*Polynomial) *macaulayMatrix {
+	mat := &macaulayMatrix{proto: rows[0], cols: make(map[string]int)}
+	for _, r := range rows {
+		for _, m := range r.items {
+			key := termKey(m.T)
+			if _, ok := mat.cols[key]; !ok {
+				mat.cols[key] = -1
+				mat.terms = append(mat.terms, m.T)
+			}
+		}
+	}
+	sort.Sort(termSorter{mat.terms, mat.proto.order})
+	for i, t := range mat.terms {
+		mat.cols[termKey(t)] = i
+	}
+	mat.rows = make([]sparseRow, len(rows))
+	for i, r := range rows {
+		row := make(sparseRow, len(r.items))
+		for j, m := range r.items {
+			row[j] = sparseEntry{mat.cols[termKey(m.T)], m.C}
+		}
+		mat.rows[i] = row
+	}
+	return mat
+}
+
+// reduce eliminates the pivot of row repeatedly with the pivot rows until
+// the leading column of the row has no pivot anymore.
+func (mat *macaulayMatrix) reduce(row sparseRow, pivots map[int]sparseRow) sparseRow {
+	f := mat.proto.field
+	for len(row) > 0 {
+		p, ok := pivots[row[0].col]
+		if !ok {
+			break
+		}
+		c := f.Quo(row[0].c, p[0].c)
+		row = mat.combine(row, p, f.Neg(c))
+	}
+	return row
+}
+
+// combine calculates a + c*b.
+func (mat *macaulayMatrix) combine(a, b sparseRow, c Coeff) sparseRow {
+	f := mat.proto.field
+	rval := make(sparseRow, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j >= len(b) || i < len(a) && a[i].col < b[j].col:
+			rval = append(rval, a[i])
+			i++
+		case i >= len(a) || b[j].col < a[i].col:
+			rval = append(rval, sparseEntry{b[j].col, f.Mul(c, b[j].c)})
+			j++
+		default:
+			if x := f.Add(a[i].c, f.Mul(c, b[j].c)); !f.IsZero(x) {
+				rval = append(rval, sparseEntry{a[i].col, x})
+			}
+			i++
+			j++
+		}
+	}
+	return rval
+}
+
+func (mat *macaulayMatrix) monic(row sparseRow) sparseRow {
+	f := mat.proto.field
+	inv := f.Quo(f.One(), row[0].c)
+	rval := make(sparseRow, len(row))
+	for i := range row {
+		rval[i] = sparseEntry{row[i].col, f.Mul(inv, row[i].c)}
+	}
+	return rval
+}
+
+func (mat *macaulayMatrix) polynomial(row sparseRow) *Polynomial {
+	p := &Polynomial{vars: mat.proto.vars, order: mat.proto.order,
+		field: mat.proto.field}
+	p.items = make([]Monomial, len(row))
+	for i := range row {
+		p.items[i] = Monomial{row[i].c, mat.terms[row[i].col]}
+	}
+	return p
+}
+
+// termSorter sorts terms in descending order.
+type termSorter struct {
+	terms []Term
+	order TermOrder
+}
+
+func (s termSorter) Less(i, j int) bool {
+	return s.order(s.terms[j], s.terms[i])
+}
+
+func (s termSorter) Swap(i, j int) {
+	s.terms[i], s.terms[j] = s.terms[j], s.terms[i]
+}
+
+func (s termSorter) Len() int {
+	return len(s.terms)
+}
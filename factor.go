@@ -0,0 +1,593 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+)
+
+// The functions in this file factor univariate polynomials over finite
+// prime fields (Cantor and Zassenhaus) and over the rationals (Zassenhaus).
+// Integer polynomials are stored like the dense polynomials over a field,
+// but with *big.Int coefficients.
+
+// upolyFactorPrime factors the monic square free polynomial a over the
+// prime field into monic irreducible factors. The distinct degree
+// factorization splits a into products of factors of equal degree d, using
+// that x^(p^d) - x is the product of all irreducible polynomials whose
+// degree divides d. The products are split by the probabilistic equal
+// degree factorization. The characteristic must be odd.
+func upolyFactorPrime(f *PrimeField, a []Coeff, rnd *rand.Rand) [][]Coeff {
+	var factors [][]Coeff
+	x := []Coeff{f.Zero(), f.One()}
+	h := x
+	p := new(big.Int).SetUint64(f.p)
+	for d := 1; 2*d <= upolyDegree(a); d++ {
+		h = upolyPowMod(f, h, p, a)
+		g := upolyGCD(f, upolySub(f, h, x), a)
+		if upolyDegree(g) > 0 {
+			factors = append(factors, upolySplitEqualDegree(f, g, d, rnd)...)
+			a, _ = upolyDivMod(f, a, g)
+			h = upolyMod(f, h, a)
+		}
+	}
+	if upolyDegree(a) > 0 {
+		factors = append(factors, upolyMonic(f, a))
+	}
+	return factors
+}
+
+// upolySplitEqualDegree splits the monic square free polynomial a, whose
+// irreducible factors all have degree d. For a random polynomial r,
+// gcd(r^((p^d-1)/2) - 1, a) is a proper factor with probability 1/2.
+func upolySplitEqualDegree(f *PrimeField, a []Coeff, d int, rnd *rand.Rand) [][]Coeff {
+	if upolyDegree(a) <= d {
+		return [][]Coeff{a}
+	}
+	e := new(big.Int).Exp(new(big.Int).SetUint64(f.p), big.NewInt(int64(d)), nil)
+	e.Rsh(e.Sub(e, big.NewInt(1)), 1)
+	for {
+		r := make([]Coeff, upolyDegree(a))
+		for i := range r {
+			r[i] = uint64(rnd.Int63n(int64(f.p)))
+		}
+		r = upolyTrim(f, r)
+		if upolyDegree(r) < 1 {
+			continue
+		}
+		b := upolySub(f, upolyPowMod(f, r, e, a), []Coeff{f.One()})
+		g := upolyGCD(f, b, a)
+		if upolyDegree(g) > 0 && upolyDegree(g) < upolyDegree(a) {
+			q, _ := upolyDivMod(f, a, g)
+			return append(upolySplitEqualDegree(f, g, d, rnd),
+				upolySplitEqualDegree(f, upolyMonic(f, q), d, rnd)...)
+		}
+	}
+}
+
+// upolyFactorRationals factors the square free polynomial a over the
+// rationals into monic irreducible factors. The primitive integer multiple
+// of a is factored modulo a small prime, the factors are lifted with
+// Hensel's lemma to a power of the prime exceeding the Mignotte bound and
+// the true factors are found by trying all combinations of lifted factors.
+func upolyFactorRationals(a []Coeff) [][]Coeff {
+	if upolyDegree(a) < 1 {
+		return nil
+	}
+	if upolyDegree(a) == 1 {
+		return [][]Coeff{upolyMonic(Rationals, a)}
+	}
+	A := zpolyPrimitive(a)
+	p, modular := zpolyFactorModPrime(A)
+	if len(modular) == 1 {
+		return [][]Coeff{upolyMonic(Rationals, a)}
+	}
+
+	// the coefficients of lc(A) times a factor of A are bounded by
+	// |lc(A)| * 2^deg(A) * ||A||, the modulus must exceed twice that bound
+	bound := new(big.Int)
+	for _, c := range A {
+		if x := new(big.Int).Abs(c); x.Cmp(bound) > 0 {
+			bound = x
+		}
+	}
+	bound.Mul(bound, big.NewInt(int64(len(A))))
+	bound.Mul(bound, new(big.Int).Abs(A[len(A)-1]))
+	bound.Lsh(bound, uint(len(A)))
+	pk, k := new(big.Int).SetUint64(p), 1
+	for pk.Cmp(bound) <= 0 {
+		pk.Mul(pk, new(big.Int).SetUint64(p))
+		k++
+	}
+	lifted := zpolyHenselLift(A, modular, p, k)
+
+	var factors [][]Coeff
+	for size := 1; 2*size <= len(lifted); size++ {
+		for _, subset := range combinations(len(lifted), size) {
+			cand := []*big.Int{new(big.Int).Set(A[len(A)-1])}
+			for _, i := range subset {
+				cand = zpolyMod(zpolyMul(cand, lifted[i]), pk)
+			}
+			cand = zpolySymmetric(cand, pk)
+			q, r := upolyDivMod(Rationals, zpolyToRat(A), zpolyToRat(cand))
+			if len(r) > 0 {
+				continue
+			}
+			factors = append(factors, upolyMonic(Rationals, zpolyToRat(cand)))
+			A = zpolyPrimitive(q)
+			var rest [][]*big.Int
+			for i := range lifted {
+				found := false
+				for _, j := range subset {
+					found = found || i == j
+				}
+				if !found {
+					rest = append(rest, lifted[i])
+				}
+			}
+			lifted = rest
+			// restart the search with the remaining factors
+			size = 0
+			break
+		}
+	}
+	return append(factors, upolyMonic(Rationals, zpolyToRat(A)))
+}
+
+// combinations returns all subsets of {0, ..., n-1} with k elements.
+func combinations(n, k int) [][]int {
+	var rval [][]int
+	var rec func(start int, cur []int)
+	rec = func(start int, cur []int) {
+		if len(cur) == k {
+			rval = append(rval, append([]int{}, cur...))
+			return
+		}
+		for i := start; i < n; i++ {
+			rec(i+1, append(cur, i))
+		}
+	}
+	rec(0, nil)
+	return rval
+}
+
+// zpolyFactorModPrime factors A modulo several small primes, which do not
+// divide the leading coefficient and keep A square free, and returns the
+// factorization with the fewest factors.
+func zpolyFactorModPrime(A []*big.Int) (uint64, [][]Coeff) {
+	var (
+		best    [][]Coeff
+		bestP   uint64
+		tries   = 0
+		rnd     = rand.New(rand.NewSource(1))
+		modulus = new(big.Int)
+	)
+	for p := uint64(3); tries < 5; p += 2 {
+		if q := primeFactors(p); len(q) != 1 || q[0] != p {
+			continue
+		}
+		modulus.SetUint64(p)
+		if new(big.Int).Mod(A[len(A)-1], modulus).Sign() == 0 {
+			continue
+		}
+		f, _ := NewPrimeField(p)
+		a := zpolyToPrime(f, A)
+		if upolyDegree(upolyGCD(f, a, upolyDeriv(f, a))) > 0 {
+			continue
+		}
+		tries++
+		factors := upolyFactorPrime(f, upolyMonic(f, a), rnd)
+		if best == nil || len(factors) < len(best) {
+			best, bestP = factors, p
+		}
+	}
+	return bestP, best
+}
+
+// zpolyHenselLift lifts the monic factors of A modulo p to monic factors
+// modulo p^k. The factors are split off one after another: A = g*h is
+// lifted for the first factor g and the product h of the others, and the
+// lifted h is factored further.
+func zpolyHenselLift(A []*big.Int, factors [][]Coeff, p uint64, k int) [][]*big.Int {
+	f, _ := NewPrimeField(p)
+	pk := new(big.Int).Exp(new(big.Int).SetUint64(p), big.NewInt(int64(k)), nil)
+	rval := make([][]*big.Int, 0, len(factors))
+	rest := A
+	for i := 0; i < len(factors)-1; i++ {
+		h := zpolyToPrime(f, rest[len(rest)-1:])
+		for _, g := range factors[i+1:] {
+			h = upolyMul(f, h, g)
+		}
+		G, H := zpolyHenselStep(rest, factors[i], h, f, k)
+		rval = append(rval, G)
+		rest = H
+	}
+	// make the last factor monic
+	inv := new(big.Int).ModInverse(rest[len(rest)-1], pk)
+	last := make([]*big.Int, len(rest))
+	for i := range rest {
+		last[i] = new(big.Int).Mul(rest[i], inv)
+	}
+	return append(rval, zpolyMod(last, pk))
+}
+
+// zpolyHenselStep lifts the factorization A = g*h modulo p with monic g to
+// a factorization modulo p^k by linear Hensel lifting. In every step the
+// error e = A - G*H, which is divisible by q = p^j, is corrected by
+// G += q*dg and H += q*dh with g*dh + h*dg = e/q modulo p.
+func zpolyHenselStep(A []*big.Int, g, h []Coeff, f *PrimeField, k int) (G, H []*big.Int) {
+	one, s := upolyExtGCD(f, g, h)
+	t, _ := upolyDivMod(f, upolySub(f, one, upolyMul(f, s, g)), h)
+	G, H = zpolyFromPrime(g), zpolyFromPrime(h)
+	p := new(big.Int).SetUint64(f.p)
+	q := new(big.Int).Set(p)
+	for j := 1; j < k; j++ {
+		e := zpolySub(A, zpolyMul(G, H))
+		for i := range e {
+			e[i].Quo(e[i], q)
+		}
+		c := zpolyToPrime(f, e)
+		quo, dg := upolyDivMod(f, upolyMul(f, t, c), g)
+		dh := upolyAdd(f, upolyMul(f, s, c), upolyMul(f, quo, h))
+		next := new(big.Int).Mul(q, p)
+		G = zpolyMod(zpolyAdd(G, zpolyScale(zpolyFromPrime(dg), q)), next)
+		H = zpolyMod(zpolyAdd(H, zpolyScale(zpolyFromPrime(dh), q)), next)
+		q = next
+	}
+	return G, H
+}
+
+// zpolyPrimitive returns the primitive integer polynomial with positive
+// leading coefficient, which is a rational multiple of a.
+func zpolyPrimitive(a []Coeff) []*big.Int {
+	den := big.NewInt(1)
+	for _, c := range a {
+		d := c.(*big.Rat).Denom()
+		g := new(big.Int).GCD(nil, nil, den, d)
+		den.Mul(den, new(big.Int).Quo(d, g))
+	}
+	A := make([]*big.Int, len(a))
+	content := new(big.Int)
+	for i, c := range a {
+		x := c.(*big.Rat)
+		A[i] = new(big.Int).Mul(x.Num(), new(big.Int).Quo(den, x.Denom()))
+		content.GCD(nil, nil, content, new(big.Int).Abs(A[i]))
+	}
+	if A[len(A)-1].Sign() < 0 {
+		content.Neg(content)
+	}
+	for i := range A {
+		A[i].Quo(A[i], content)
+	}
+	return A
+}
+
+func zpolyToRat(a []*big.Int) []Coeff {
+	c := make([]Coeff, len(a))
+	for i := range a {
+		c[i] = new(big.Rat).SetInt(a[i])
+	}
+	return upolyTrim(Rationals, c)
+}
+
+func zpolyToPrime(f *PrimeField, a []*big.Int) []Coeff {
+	p := new(big.Int).SetUint64(f.p)
+	c := make([]Coeff, len(a))
+	for i := range a {
+		c[i] = new(big.Int).Mod(a[i], p).Uint64()
+	}
+	return upolyTrim(f, c)
+}
+
+func zpolyFromPrime(a []Coeff) []*big.Int {
+	c := make([]*big.Int, len(a))
+	for i := range a {
+		c[i] = new(big.Int).SetUint64(a[i].(uint64))
+	}
+	return c
+}
+
+func zpolyTrim(a []*big.Int) []*big.Int {
+	n := len(a)
+	for n > 0 && a[n-1].Sign() == 0 {
+		n--
+	}
+	return a[:n]
+}
+
+func zpolyAdd(a, b []*big.Int) []*big.Int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	c := make([]*big.Int, len(a))
+	for i := range a {
+		c[i] = new(big.Int).Set(a[i])
+		if i < len(b) {
+			c[i].Add(c[i], b[i])
+		}
+	}
+	return zpolyTrim(c)
+}
+
+func zpolySub(a, b []*big.Int) []*big.Int {
+	c := make([]*big.Int, len(b))
+	for i := range b {
+		c[i] = new(big.Int).Neg(b[i])
+	}
+	return zpolyAdd(a, c)
+}
+
+func zpolyScale(a []*big.Int, s *big.Int) []*big.Int {
+	c := make([]*big.Int, len(a))
+	for i := range a {
+		c[i] = new(big.Int).Mul(a[i], s)
+	}
+	return zpolyTrim(c)
+}
+
+func zpolyMul(a, b []*big.Int) []*big.Int {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	c := make([]*big.Int, len(a)+len(b)-1)
+	for i := range c {
+		c[i] = new(big.Int)
+	}
+	x := new(big.Int)
+	for i := range a {
+		for j := range b {
+			c[i+j].Add(c[i+j], x.Mul(a[i], b[j]))
+		}
+	}
+	return zpolyTrim(c)
+}
+
+// zpolyMod reduces every coefficient of a modulo m into the range [0, m).
+func zpolyMod(a []*big.Int, m *big.Int) []*big.Int {
+	c := make([]*big.Int, len(a))
+	for i := range a {
+		c[i] = new(big.Int).Mod(a[i], m)
+	}
+	return zpolyTrim(c)
+}
+
+// zpolySymmetric maps the coefficients of a modulo m into the range
+// (-m/2, m/2].
+func zpolySymmetric(a []*big.Int, m *big.Int) []*big.Int {
+	half := new(big.Int).Rsh(m, 1)
+	c := make([]*big.Int, len(a))
+	for i := range a {
+		c[i] = new(big.Int).Mod(a[i], m)
+		if c[i].Cmp(half) > 0 {
+			c[i].Sub(c[i], m)
+		}
+	}
+	return zpolyTrim(c)
+}
+
+// upolyFactorExtension factors the square free polynomial a over the
+// algebraic number field f with Trager's algorithm. The norm N(x) of
+// a(x - s*alpha), which is a polynomial over the rationals, is square free
+// for almost all s. Then the irreducible factors of a(x - s*alpha) are the
+// gcds with the irreducible factors of N over the rationals.
+func upolyFactorExtension(f *ExtensionField, a []Coeff) [][]Coeff {
+	if upolyDegree(a) < 1 {
+		return nil
+	}
+	if upolyDegree(a) == 1 {
+		return [][]Coeff{upolyMonic(f, a)}
+	}
+	for s := int64(0); ; s++ {
+		c, _ := f.FromRat(big.NewRat(s, 1))
+		shift := f.Mul(c, f.Generator())
+		g := upolySubst(f, a, []Coeff{f.Neg(shift), f.One()})
+		N := upolyNorm(f, g)
+		if upolyDegree(upolyGCD(Rationals, N, upolyDeriv(Rationals, N))) > 0 {
+			continue
+		}
+		var factors [][]Coeff
+		for _, n := range upolyFactorRationals(N) {
+			h := make([]Coeff, len(n))
+			for i := range n {
+				h[i], _ = f.FromRat(n[i].(*big.Rat))
+			}
+			h = upolyGCD(f, h, g)
+			factors = append(factors, upolySubst(f, h, []Coeff{shift, f.One()}))
+		}
+		return factors
+	}
+}
+
+// upolySubst calculates a(b) with Horner's scheme.
+func upolySubst(f Field, a, b []Coeff) []Coeff {
+	var c []Coeff
+	for i := len(a) - 1; i >= 0; i-- {
+		c = upolyAdd(f, upolyMul(f, c, b), []Coeff{a[i]})
+	}
+	return c
+}
+
+// upolyNorm calculates the norm of a over the rationals, the product of
+// all conjugates of a. It is the determinant of the multiplication with a
+// on the basis 1, alpha, ..., alpha^(d-1) of f[x] over QQ[x].
+func upolyNorm(f *ExtensionField, a []Coeff) []Coeff {
+	d := f.Degree()
+	M := &Matrix{vars: []string{"x"}, order: LexTermOrder, field: Rationals, cols: d}
+	rows := make([][][]Coeff, d)
+	for i := range rows {
+		rows[i] = make([][]Coeff, d)
+	}
+	power := f.One()
+	for j := 0; j < d; j++ {
+		for k, c := range a {
+			for i, e := range f.Mul(c, power).([]Coeff) {
+				for len(rows[i][j]) <= k {
+					rows[i][j] = append(rows[i][j], Rationals.Zero())
+				}
+				rows[i][j][k] = e
+			}
+		}
+		power = f.Mul(power, f.Generator())
+	}
+	for i := range rows {
+		row := make([]*Polynomial, d)
+		for j := range row {
+			row[j] = upolyToPolynomial(Rationals, upolyTrim(Rationals, rows[i][j]), M.vars, 0, M.order)
+		}
+		M.rows = append(M.rows, row)
+	}
+	det, _ := M.Det()
+	a, _ = upolyFromPolynomial(det)
+	return a
+}
+
+// upolyFactor factors a into its leading coefficient and monic irreducible
+// factors with their multiplicities.
+func upolyFactor(f Field, a []Coeff) (lc Coeff, factors [][]Coeff, mult []int, err error) {
+	if len(a) == 0 {
+		return f.Zero(), nil, nil, nil
+	}
+	s := upolySquarefree(f, a)
+	var irred [][]Coeff
+	switch f := f.(type) {
+	case rationalField:
+		irred = upolyFactorRationals(s)
+	case *PrimeField:
+		if f.p == 2 {
+			return nil, nil, nil, fmt.Errorf("factor is not supported over %v", f)
+		}
+		irred = upolyFactorPrime(f, s, rand.New(rand.NewSource(1)))
+	case *ExtensionField:
+		if f.base != Rationals {
+			return nil, nil, nil, fmt.Errorf("factor is not supported over %v", f)
+		}
+		irred = upolyFactorExtension(f, s)
+	default:
+		return nil, nil, nil, fmt.Errorf("factor is not supported over %v", f)
+	}
+	lc = a[len(a)-1]
+	a = upolyMonic(f, a)
+	for _, q := range irred {
+		n := 0
+		for {
+			quo, r := upolyDivMod(f, a, q)
+			if len(r) > 0 {
+				break
+			}
+			a = quo
+			n++
+		}
+		factors = append(factors, q)
+		mult = append(mult, n)
+	}
+	return lc, factors, mult, nil
+}
+
+// primitive returns the primitive integer multiple of the polynomial p with
+// rational coefficients, whose leading coefficient is positive.
+func (p *Polynomial) primitive() *Polynomial {
+	if len(p.items) == 0 {
+		return p
+	}
+	a := make([]Coeff, len(p.items))
+	for i, m := range p.items {
+		a[len(a)-1-i] = m.C
+	}
+	A := zpolyPrimitive(a)
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	rval.items = make([]Monomial, len(p.items))
+	for i, m := range p.items {
+		rval.items[i] = Monomial{new(big.Rat).SetInt(A[len(A)-1-i]), m.T}
+	}
+	return rval
+}
+
+// polyFactorRationals factors the multivariate polynomial p over the
+// rationals into primitive irreducible integer polynomials with their
+// multiplicities, constant factors are omitted. Kronecker's substitution
+// x_k -> z^(D^k) maps p to a univariate polynomial, where D exceeds all
+// partial degrees of p. Every factor of p is mapped to a product of
+// irreducible factors of the image, and the substitution can be inverted
+// on polynomials of smaller partial degrees. The products of the fewest
+// factors, whose preimages divide p, are its irreducible factors.
+func polyFactorRationals(p *Polynomial) (factors []*Polynomial, mult []int) {
+	rest := p.primitive()
+	for !isConstant(rest) {
+		var used []int
+		D := 1
+		for i := range rest.vars {
+			deg := 0
+			for _, m := range rest.items {
+				if e, _ := m.T.Int(i); e > deg {
+					deg = e
+				}
+			}
+			if deg > 0 {
+				used = append(used, i)
+			}
+			if deg+1 > D {
+				D = deg + 1
+			}
+		}
+		var a []Coeff
+		for _, m := range rest.items {
+			e, w := 0, 1
+			for _, i := range used {
+				k, _ := m.T.Int(i)
+				e, w = e+k*w, w*D
+			}
+			for len(a) <= e {
+				a = append(a, new(big.Rat))
+			}
+			a[e] = m.C
+		}
+		_, ufactors, umult, _ := upolyFactor(Rationals, a)
+		var images [][]Coeff
+		for i, f := range ufactors {
+			for j := 0; j < umult[i]; j++ {
+				images = append(images, f)
+			}
+		}
+
+		var factor *Polynomial
+		for size := 1; size <= len(images) && factor == nil; size++ {
+			for _, subset := range combinations(len(images), size) {
+				b := []Coeff{big.NewRat(1, 1)}
+				for _, i := range subset {
+					b = upolyMul(Rationals, b, images[i])
+				}
+				// invert the substitution on the primitive multiple
+				cand := &Polynomial{vars: rest.vars, order: rest.order, field: rest.field}
+				for e, c := range zpolyPrimitive(b) {
+					if c.Sign() == 0 {
+						continue
+					}
+					t := NewTerm(len(rest.vars))
+					for k, x := 0, e; x > 0; k, x = k+1, x/D {
+						t = t.addExp(used[k], big.NewRat(int64(x%D), 1))
+					}
+					cand.items = append(cand.items, Monomial{new(big.Rat).SetInt(c), t})
+				}
+				cand.normalize()
+				if _, r := rest.divide([]*Polynomial{cand}); r.IsZero() {
+					factor = cand.primitive()
+					break
+				}
+			}
+		}
+		n := 0
+		for {
+			quo, r := rest.divide([]*Polynomial{factor})
+			if !r.IsZero() {
+				break
+			}
+			rest = quo[0]
+			n++
+		}
+		factors, mult = append(factors, factor), append(mult, n)
+	}
+	return factors, mult
+}
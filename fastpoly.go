@@ -0,0 +1,311 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+)
+
+// The functions in this file implement asymptotically fast algorithms for
+// the dense univariate polynomials: Karatsuba multiplication, division with
+// remainder by Newton iteration, the half-GCD algorithm and multipoint
+// evaluation with a subproduct tree. The classical algorithms are faster
+// for small polynomials, so the fast ones are only used above thresholds.
+
+const (
+	karatsubaThreshold = 32
+	newtonThreshold    = 64
+	halfGCDThreshold   = 64
+)
+
+// upolyKaratsuba multiplies a and b by splitting both polynomials into a
+// lower and an upper half a = a1*x^k + a0 and b = b1*x^k + b0. The product
+// is a1*b1*x^(2k) + ((a0+a1)*(b0+b1) - a0*b0 - a1*b1)*x^k + a0*b0, which
+// needs three instead of four multiplications of half the size.
+func upolyKaratsuba(f Field, a, b []Coeff) []Coeff {
+	if len(a) < karatsubaThreshold || len(b) < karatsubaThreshold {
+		return upolyMulClassical(f, a, b)
+	}
+	k := len(a)
+	if len(b) > k {
+		k = len(b)
+	}
+	k /= 2
+	a0, a1 := upolySplit(f, a, k)
+	b0, b1 := upolySplit(f, b, k)
+	low := upolyKaratsuba(f, a0, b0)
+	high := upolyKaratsuba(f, a1, b1)
+	mid := upolyKaratsuba(f, upolyAdd(f, a0, a1), upolyAdd(f, b0, b1))
+	mid = upolySub(f, upolySub(f, mid, low), high)
+	c := upolyAdd(f, low, upolyShift(f, mid, k))
+	return upolyAdd(f, c, upolyShift(f, high, 2*k))
+}
+
+// upolySplit splits a into a0 + a1*x^k.
+func upolySplit(f Field, a []Coeff, k int) (a0, a1 []Coeff) {
+	if len(a) <= k {
+		return a, nil
+	}
+	return upolyTrim(f, a[:k]), a[k:]
+}
+
+// upolyShift multiplies a with x^k.
+func upolyShift(f Field, a []Coeff, k int) []Coeff {
+	if len(a) == 0 {
+		return nil
+	}
+	c := make([]Coeff, k+len(a))
+	for i := 0; i < k; i++ {
+		c[i] = f.Zero()
+	}
+	copy(c[k:], a)
+	return c
+}
+
+// upolyTruncate returns a modulo x^n.
+func upolyTruncate(f Field, a []Coeff, n int) []Coeff {
+	if len(a) <= n {
+		return a
+	}
+	return upolyTrim(f, a[:n])
+}
+
+// upolyReverse returns the reversal x^n*a(1/x) of a, whose degree is at
+// most n.
+func upolyReverse(f Field, a []Coeff, n int) []Coeff {
+	c := make([]Coeff, n+1)
+	for i := range c {
+		if n-i < len(a) {
+			c[i] = a[n-i]
+		} else {
+			c[i] = f.Zero()
+		}
+	}
+	return upolyTrim(f, c)
+}
+
+// upolyInverseSeries calculates the inverse of a modulo x^n by Newton
+// iteration g = g*(2 - a*g), which doubles the number of correct
+// coefficients in every step. The constant coefficient of a must not be
+// zero.
+func upolyInverseSeries(f Field, a []Coeff, n int) []Coeff {
+	g := []Coeff{f.Quo(f.One(), a[0])}
+	two := []Coeff{f.Add(f.One(), f.One())}
+	for k := 1; k < n; {
+		k *= 2
+		if k > n {
+			k = n
+		}
+		e := upolySub(f, two, upolyTruncate(f, upolyMul(f, upolyTruncate(f, a, k), g), k))
+		g = upolyTruncate(f, upolyMul(f, g, e), k)
+	}
+	return g
+}
+
+// upolyFastDivMod calculates the quotient and remainder of the division of
+// a by b with Newton iteration. The reversed quotient is the product of the
+// reversal of a and the inverse of the reversal of b modulo x^(m-n+1), where
+// m and n are the degrees of a and b.
+func upolyFastDivMod(f Field, a, b []Coeff) (q, r []Coeff) {
+	m, n := upolyDegree(a), upolyDegree(b)
+	if m < n {
+		return nil, a
+	}
+	k := m - n + 1
+	inv := upolyInverseSeries(f, upolyReverse(f, b, n), k)
+	q = upolyTruncate(f, upolyMul(f, upolyReverse(f, a, m), inv), k)
+	q = upolyReverse(f, q, k-1)
+	r = upolySub(f, a, upolyMul(f, q, b))
+	return q, r
+}
+
+// upolyMatrix is a 2x2 matrix of polynomials, which describes a sequence
+// of steps of the euclidean algorithm.
+type upolyMatrix [2][2][]Coeff
+
+func upolyIdentity(f Field) upolyMatrix {
+	return upolyMatrix{{{f.One()}, nil}, {nil, {f.One()}}}
+}
+
+func (m upolyMatrix) mul(f Field, n upolyMatrix) upolyMatrix {
+	var rval upolyMatrix
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			rval[i][j] = upolyAdd(f, upolyMul(f, m[i][0], n[0][j]),
+				upolyMul(f, m[i][1], n[1][j]))
+		}
+	}
+	return rval
+}
+
+func (m upolyMatrix) apply(f Field, a, b []Coeff) ([]Coeff, []Coeff) {
+	return upolyAdd(f, upolyMul(f, m[0][0], a), upolyMul(f, m[0][1], b)),
+		upolyAdd(f, upolyMul(f, m[1][0], a), upolyMul(f, m[1][1], b))
+}
+
+// upolyHalfGCD calculates the matrix of the steps of the euclidean
+// algorithm on a and b, with deg a >= deg b, which reduce the degree of the
+// remainders below half of the degree of a. The quotients of the first
+// steps only depend on the upper coefficients, so they are calculated
+// recursively from the upper halves of a and b. The identity is returned
+// if deg a < deg b or if the halves would not be smaller than a and b.
+func upolyHalfGCD(f Field, a, b []Coeff) upolyMatrix {
+	m := (upolyDegree(a) + 1) / 2
+	if m == 0 || upolyDegree(a) < upolyDegree(b) || upolyDegree(b) < m {
+		return upolyIdentity(f)
+	}
+	R := upolyHalfGCD(f, a[m:], b[m:])
+	a, b = R.apply(f, a, b)
+	if upolyDegree(b) < m {
+		return R
+	}
+	q, r := upolyDivMod(f, a, b)
+	step := upolyMatrix{{nil, {f.One()}}, {{f.One()}, upolySub(f, nil, q)}}
+	R = step.mul(f, R)
+	a, b = b, r
+	k := 2*m - upolyDegree(a)
+	if k <= 0 || len(b) <= k {
+		return R
+	}
+	return upolyHalfGCD(f, a[k:], b[k:]).mul(f, R)
+}
+
+// upolyFastGCD calculates the monic greatest common divisor of a and b
+// with the half-GCD algorithm.
+func upolyFastGCD(f Field, a, b []Coeff) []Coeff {
+	if upolyDegree(a) < upolyDegree(b) {
+		a, b = b, a
+	}
+	for len(b) > 0 {
+		if len(b) < halfGCDThreshold {
+			return upolyGCD(f, a, b)
+		}
+		a, b = upolyHalfGCD(f, a, b).apply(f, a, b)
+		if len(b) == 0 {
+			break
+		}
+		// a classical step guarantees the progress
+		a, b = b, upolyMod(f, a, b)
+	}
+	return upolyMonic(f, a)
+}
+
+// upolyEval evaluates a at x with Horner's scheme.
+func upolyEval(f Field, a []Coeff, x Coeff) Coeff {
+	v := f.Zero()
+	for i := len(a) - 1; i >= 0; i-- {
+		v = f.Add(f.Mul(v, x), a[i])
+	}
+	return v
+}
+
+// upolyEvalMany evaluates a at all points. The subproduct tree contains the
+// products of the linear factors x - p_i of the points in its subtrees, and
+// a is reduced modulo these products from the root to the leaves, where
+// the remainders are the values.
+func upolyEvalMany(f Field, a []Coeff, points []Coeff) []Coeff {
+	if len(points) == 0 {
+		return nil
+	}
+	if len(points) == 1 {
+		return []Coeff{upolyEval(f, a, points[0])}
+	}
+	tree := [][][]Coeff{make([][]Coeff, len(points))}
+	for i, p := range points {
+		tree[0][i] = upolyTrim(f, []Coeff{f.Neg(p), f.One()})
+	}
+	for len(tree[len(tree)-1]) > 1 {
+		level := tree[len(tree)-1]
+		next := make([][]Coeff, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = upolyMul(f, level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		tree = append(tree, next)
+	}
+	rems := [][]Coeff{upolyMod(f, a, tree[len(tree)-1][0])}
+	for k := len(tree) - 2; k >= 0; k-- {
+		level := tree[k]
+		next := make([][]Coeff, len(level))
+		for i := range level {
+			next[i] = upolyMod(f, rems[i/2], level[i])
+		}
+		rems = next
+	}
+	values := make([]Coeff, len(points))
+	for i, r := range rems {
+		values[i] = f.Zero()
+		if len(r) > 0 {
+			values[i] = r[0]
+		}
+	}
+	return values
+}
+
+// isDense reports whether p is a univariate polynomial with non-negative
+// integer exponents, which is stored efficiently in the dense
+// representation.
+func isDense(p *Polynomial) bool {
+	if len(p.vars) != 1 {
+		return false
+	}
+	for _, m := range p.items {
+		e, ok := m.T.Int(0)
+		if !ok || e < 0 || e >= 2*len(p.items) {
+			return false
+		}
+	}
+	return true
+}
+
+// mulDense multiplies two large dense univariate polynomials in the dense
+// representation. The second return value is false if p or q is not dense.
+func (p *Polynomial) mulDense(q *Polynomial) (*Polynomial, bool) {
+	if len(p.items) < karatsubaThreshold || len(q.items) < karatsubaThreshold ||
+		!isDense(p) || !isDense(q) {
+		return nil, false
+	}
+	a, err := upolyFromPolynomial(p)
+	if err != nil {
+		return nil, false
+	}
+	b, err := upolyFromPolynomial(q)
+	if err != nil {
+		return nil, false
+	}
+	return p.fromDense(upolyMul(p.field, a, b)), true
+}
+
+// fromDense converts a into a polynomial in the variables of p, which has
+// at most one variable.
+func (p *Polynomial) fromDense(a []Coeff) *Polynomial {
+	if len(p.vars) == 0 {
+		rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+		if len(a) > 0 {
+			rval.items = []Monomial{{a[0], NewTerm(0)}}
+		}
+		return rval
+	}
+	return upolyToPolynomial(p.field, a, p.vars, 0, p.order)
+}
+
+// univariate converts polynomials in the same ring into the dense
+// representation.
+func univariate(fns []*Polynomial) ([][]Coeff, error) {
+	if len(fns) == 0 {
+		return nil, errors.New("expected at least one polynomial")
+	}
+	rval := make([][]Coeff, len(fns))
+	for i, p := range fns {
+		a, err := upolyFromPolynomial(p)
+		if err != nil {
+			return nil, err
+		}
+		rval[i] = a
+	}
+	return rval, nil
+}
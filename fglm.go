@@ -0,0 +1,140 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// fglmRow is a row of the echelon form maintained by FGLM. nf is a linear
+// combination of normal forms and comb is the same linear combination of
+// the corresponding standard monomials, written in the new term order.
+type fglmRow struct {
+	nf   *Polynomial
+	comb *Polynomial
+}
+
+// FGLM converts the Gröbner basis fns of a zero-dimensional ideal into the
+// reduced Gröbner basis with respect to the term order order (Faugère,
+// Gianni, Lazard and Mora). The terms are enumerated in increasing order.
+// The normal form of every term, which is not a multiple of a leading term
+// found so far, is reduced by the normal forms of the previous standard
+// monomials. If it reduces to zero, the linear dependency is a new element
+// of the basis, otherwise the term is a new standard monomial.
+func FGLM(fns []*Polynomial, order TermOrder) ([]*Polynomial, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if !isGroebner(fns) {
+		return nil, errors.New("fglm requires a Gröbner basis")
+	}
+	if !isZeroDimensional(fns) {
+		return nil, errors.New("fglm requires a zero-dimensional ideal")
+	}
+	var (
+		proto  = fns[0]
+		field  = proto.field
+		n      = len(proto.vars)
+		basis  []*Polynomial
+		pivots = make(map[string]fglmRow)
+		seen   = make(map[string]bool)
+		nfs    = make(map[string]*Polynomial)
+	)
+	one := NewTerm(n)
+	unit := &Polynomial{vars: proto.vars, order: proto.order, field: field,
+		items: []Monomial{{field.One(), one}}}
+	nfs[termKey(one)] = unit.NormalForm(fns)
+	candidates := []Term{one}
+	for len(candidates) > 0 {
+		sort.Sort(termSorter{candidates, order})
+		t := candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+		if seen[termKey(t)] || fglmDivisible(basis, t) {
+			continue
+		}
+		seen[termKey(t)] = true
+
+		// the normal form of x_i*s is the normal form of x_i*NF(s)
+		nf, ok := nfs[termKey(t)]
+		if !ok {
+			for i := 0; i < n && nf == nil; i++ {
+				if t.Sign(i) == 0 {
+					continue
+				}
+				if s, ok := nfs[termKey(t.addExp(i, big.NewRat(-1, 1)))]; ok {
+					nf = s.MulMonomial(field.One(), one.addExp(i, big.NewRat(1, 1)))
+					nf = nf.NormalForm(fns)
+				}
+			}
+		}
+		orig := nf
+		comb := &Polynomial{vars: proto.vars, order: order, field: field,
+			items: []Monomial{{field.One(), t}}}
+		for i := 0; i < len(nf.items); {
+			row, ok := pivots[termKey(nf.items[i].T)]
+			if !ok {
+				i++
+				continue
+			}
+			c := nf.items[i].C
+			nf = nf.Sub(row.nf.MulMonomial(c, one))
+			comb = comb.Sub(row.comb.MulMonomial(c, one))
+		}
+		if nf.IsZero() {
+			basis = append(basis, comb)
+			continue
+		}
+		inv := field.Quo(field.One(), nf.items[0].C)
+		pivots[termKey(nf.items[0].T)] = fglmRow{
+			nf.MulMonomial(inv, one), comb.MulMonomial(inv, one)}
+		nfs[termKey(t)] = orig
+		for i := 0; i < n; i++ {
+			candidates = append(candidates, t.addExp(i, big.NewRat(1, 1)))
+		}
+	}
+	sort.Sort(basisSorter(basis))
+	return basis, nil
+}
+
+// fglmDivisible reports whether t is a multiple of a leading term of basis.
+func fglmDivisible(basis []*Polynomial, t Term) bool {
+	for _, g := range basis {
+		if termDivides(g.items[0].T, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZeroDimensional reports whether the ideal generated by the Gröbner
+// basis fns has finitely many solutions, i.e. whether a pure power of every
+// variable is a leading term.
+func isZeroDimensional(fns []*Polynomial) bool {
+	n := len(fns[0].vars)
+	for _, f := range fns {
+		if f.items[0].T.Degree().Sign() == 0 {
+			return true
+		}
+	}
+	for i := 0; i < n; i++ {
+		found := false
+		for _, f := range fns {
+			t := f.items[0].T
+			pure := t.Sign(i) > 0
+			for j := 0; j < n && pure; j++ {
+				pure = j == i || t.Sign(j) == 0
+			}
+			if pure {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,490 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Coeff is an element of a coefficient field. The concrete type depends on
+// the field it belongs to. Coefficients are treated as immutable values, all
+// field operations return newly allocated results.
+type Coeff interface{}
+
+// Field describes the arithmetic of the coefficients of a polynomial.
+type Field interface {
+	Zero() Coeff
+	One() Coeff
+	FromRat(x *big.Rat) (Coeff, error)
+	IsZero(a Coeff) bool
+	Equal(a, b Coeff) bool
+	Add(a, b Coeff) Coeff
+	Sub(a, b Coeff) Coeff
+	Mul(a, b Coeff) Coeff
+	// Quo panics if b is zero, like the division of rational numbers, so
+	// the callers have to check the divisor first.
+	Quo(a, b Coeff) Coeff
+	Neg(a Coeff) Coeff
+	Format(a Coeff) string
+	String() string
+}
+
+// Rationals is the field of rational numbers. Its elements are *big.Rat.
+var Rationals Field = rationalField{}
+
+type rationalField struct{}
+
+func (rationalField) Zero() Coeff { return new(big.Rat) }
+func (rationalField) One() Coeff  { return big.NewRat(1, 1) }
+
+func (rationalField) FromRat(x *big.Rat) (Coeff, error) {
+	return new(big.Rat).Set(x), nil
+}
+
+func (rationalField) IsZero(a Coeff) bool {
+	return a.(*big.Rat).Sign() == 0
+}
+
+func (rationalField) Equal(a, b Coeff) bool {
+	return a.(*big.Rat).Cmp(b.(*big.Rat)) == 0
+}
+
+func (rationalField) Add(a, b Coeff) Coeff {
+	return new(big.Rat).Add(a.(*big.Rat), b.(*big.Rat))
+}
+
+func (rationalField) Sub(a, b Coeff) Coeff {
+	return new(big.Rat).Sub(a.(*big.Rat), b.(*big.Rat))
+}
+
+func (rationalField) Mul(a, b Coeff) Coeff {
+	return new(big.Rat).Mul(a.(*big.Rat), b.(*big.Rat))
+}
+
+func (rationalField) Quo(a, b Coeff) Coeff {
+	return new(big.Rat).Quo(a.(*big.Rat), b.(*big.Rat))
+}
+
+func (rationalField) Neg(a Coeff) Coeff {
+	return new(big.Rat).Neg(a.(*big.Rat))
+}
+
+func (rationalField) Format(a Coeff) string {
+	return a.(*big.Rat).RatString()
+}
+
+func (rationalField) String() string {
+	return "QQ"
+}
+
+// PrimeField is the field of integers modulo a prime p. Its elements are
+// uint64 values in the range [0, p).
+type PrimeField struct {
+	p uint64
+}
+
+// NewPrimeField returns the field of integers modulo p. The prime must be
+// smaller than 2^32 so that products of two elements fit into an uint64.
+func NewPrimeField(p uint64) (*PrimeField, error) {
+	if p < 2 || p >= 1<<32 || !new(big.Int).SetUint64(p).ProbablyPrime(20) {
+		return nil, fmt.Errorf("invalid characteristic %d", p)
+	}
+	return &PrimeField{p}, nil
+}
+
+var errDivisible = errors.New("denominator is divisible by the characteristic")
+
+func (f *PrimeField) Zero() Coeff { return uint64(0) }
+func (f *PrimeField) One() Coeff  { return uint64(1) }
+
+func (f *PrimeField) FromRat(x *big.Rat) (Coeff, error) {
+	p := new(big.Int).SetUint64(f.p)
+	num := new(big.Int).Mod(x.Num(), p).Uint64()
+	den := new(big.Int).Mod(x.Denom(), p).Uint64()
+	if den == 0 {
+		return nil, errDivisible
+	}
+	return f.Quo(num, den), nil
+}
+
+func (f *PrimeField) IsZero(a Coeff) bool {
+	return a.(uint64) == 0
+}
+
+func (f *PrimeField) Equal(a, b Coeff) bool {
+	return a.(uint64) == b.(uint64)
+}
+
+func (f *PrimeField) Add(a, b Coeff) Coeff {
+	return (a.(uint64) + b.(uint64)) % f.p
+}
+
+func (f *PrimeField) Sub(a, b Coeff) Coeff {
+	return (a.(uint64) + f.p - b.(uint64)) % f.p
+}
+
+func (f *PrimeField) Mul(a, b Coeff) Coeff {
+	return a.(uint64) * b.(uint64) % f.p
+}
+
+func (f *PrimeField) Quo(a, b Coeff) Coeff {
+	return a.(uint64) * f.inverse(b.(uint64)) % f.p
+}
+
+func (f *PrimeField) Neg(a Coeff) Coeff {
+	return (f.p - a.(uint64)) % f.p
+}
+
+// inverse calculates the multiplicative inverse of a using the extended
+// euclidean algorithm.
+func (f *PrimeField) inverse(a uint64) uint64 {
+	if a == 0 {
+		panic("division by zero")
+	}
+	t, newt := int64(0), int64(1)
+	r, newr := int64(f.p), int64(a)
+	for newr != 0 {
+		q := r / newr
+		t, newt = newt, t-q*newt
+		r, newr = newr, r-q*newr
+	}
+	if t < 0 {
+		t += int64(f.p)
+	}
+	return uint64(t)
+}
+
+func (f *PrimeField) Format(a Coeff) string {
+	return fmt.Sprint(a.(uint64))
+}
+
+func (f *PrimeField) String() string {
+	return fmt.Sprintf("GF(%d)", f.p)
+}
+
+// ExtensionField is the field K[a]/(m) obtained by adjoining a root a of
+// the irreducible polynomial m to the base field K. Its elements are
+// polynomials in a of degree smaller than m, stored as []Coeff like the
+// univariate polynomials in univariate.go.
+type ExtensionField struct {
+	base    Field
+	modulus []Coeff
+	name    string
+}
+
+// GaussianRationals is the field QQ(i) of the Gaussian rationals, obtained by
+// adjoining a root i of x^2 + 1 to the rationals. The literal i denotes its
+// generator and field(i) selects it as the coefficient field.
+var GaussianRationals = &ExtensionField{Rationals,
+	[]Coeff{big.NewRat(1, 1), big.NewRat(0, 1), big.NewRat(1, 1)}, "i"}
+
+// NewExtensionField returns the field obtained by adjoining a root of
+// modulus to base. The root is printed using the given name. The modulus
+// must be irreducible over base.
+func NewExtensionField(base Field, modulus []Coeff, name string) (*ExtensionField, error) {
+	modulus = upolyTrim(base, modulus)
+	if len(modulus) < 2 {
+		return nil, fmt.Errorf("invalid modulus")
+	}
+	return &ExtensionField{base, upolyMonic(base, modulus), name}, nil
+}
+
+// NewNumberField returns the algebraic number field Q(a) obtained by
+// adjoining a root of the univariate polynomial m to the rationals. The
+// modulus must be irreducible over the rationals.
+func NewNumberField(m *Polynomial) (*ExtensionField, error) {
+	if m.field != Rationals || len(m.vars) != 1 {
+		return nil, fmt.Errorf("invalid modulus %v", m)
+	}
+	modulus, err := upolyFromPolynomial(m)
+	if err != nil {
+		return nil, err
+	}
+	if len(modulus) < 2 {
+		return nil, fmt.Errorf("invalid modulus %v", m)
+	}
+	g := upolyGCD(Rationals, modulus, upolyDeriv(Rationals, modulus))
+	if upolyDegree(g) > 0 {
+		return nil, fmt.Errorf("modulus %v is not square free", m)
+	}
+	if len(upolyFactorRationals(modulus)) > 1 {
+		return nil, fmt.Errorf("modulus %v is not irreducible", m)
+	}
+	return NewExtensionField(Rationals, modulus, m.vars[0])
+}
+
+// Degree returns the degree of the extension.
+func (f *ExtensionField) Degree() int {
+	return upolyDegree(f.modulus)
+}
+
+// Generator returns the adjoined root.
+func (f *ExtensionField) Generator() Coeff {
+	return upolyMod(f.base, []Coeff{f.base.Zero(), f.base.One()}, f.modulus)
+}
+
+func (f *ExtensionField) Zero() Coeff { return []Coeff(nil) }
+func (f *ExtensionField) One() Coeff  { return []Coeff{f.base.One()} }
+
+func (f *ExtensionField) FromRat(x *big.Rat) (Coeff, error) {
+	c, err := f.base.FromRat(x)
+	if err != nil {
+		return nil, err
+	}
+	return upolyTrim(f.base, []Coeff{c}), nil
+}
+
+func (f *ExtensionField) IsZero(a Coeff) bool {
+	return len(a.([]Coeff)) == 0
+}
+
+func (f *ExtensionField) Equal(a, b Coeff) bool {
+	return upolyEqual(f.base, a.([]Coeff), b.([]Coeff))
+}
+
+func (f *ExtensionField) Add(a, b Coeff) Coeff {
+	return upolyAdd(f.base, a.([]Coeff), b.([]Coeff))
+}
+
+func (f *ExtensionField) Sub(a, b Coeff) Coeff {
+	return upolySub(f.base, a.([]Coeff), b.([]Coeff))
+}
+
+func (f *ExtensionField) Mul(a, b Coeff) Coeff {
+	return upolyMod(f.base, upolyMul(f.base, a.([]Coeff), b.([]Coeff)), f.modulus)
+}
+
+// Quo calculates a / b. The inverse of b is obtained with the extended
+// euclidean algorithm, since s*b = 1 modulo m for gcd(b, m) = 1, which holds
+// for every non-zero b because the modulus is irreducible.
+func (f *ExtensionField) Quo(a, b Coeff) Coeff {
+	if f.IsZero(b) {
+		panic("division by zero")
+	}
+	_, inv := upolyExtGCD(f.base, b.([]Coeff), f.modulus)
+	return f.Mul(a, upolyMod(f.base, inv, f.modulus))
+}
+
+func (f *ExtensionField) Neg(a Coeff) Coeff {
+	return upolySub(f.base, nil, a.([]Coeff))
+}
+
+func (f *ExtensionField) Format(a Coeff) string {
+	return formatUpoly(f.base, a.([]Coeff), f.name)
+}
+
+func (f *ExtensionField) String() string {
+	if f == GaussianRationals {
+		return "QQ(i)"
+	}
+	if p, ok := f.base.(*PrimeField); ok {
+		if f.Degree() == 1 {
+			return p.String()
+		}
+		return fmt.Sprintf("GF(%d^%d)", p.p, f.Degree())
+	}
+	return fmt.Sprintf("%v[%s]/%s", f.base, f.name,
+		formatUpoly(f.base, f.modulus, f.name))
+}
+
+// formatUpoly formats a univariate polynomial in the variable name.
+// Polynomials with more than one term are enclosed in parentheses.
+func formatUpoly(f Field, a []Coeff, name string) string {
+	if len(a) == 0 {
+		return "0"
+	}
+	buf := &bytes.Buffer{}
+	n := 0
+	for i := len(a) - 1; i >= 0; i-- {
+		if f.IsZero(a[i]) {
+			continue
+		}
+		if n > 0 {
+			buf.WriteString(" + ")
+		}
+		n++
+		if i == 0 || !f.Equal(a[i], f.One()) {
+			buf.WriteString(f.Format(a[i]))
+			if i > 0 {
+				buf.WriteByte('*')
+			}
+		}
+		if i > 0 {
+			buf.WriteString(name)
+		}
+		if i > 1 {
+			fmt.Fprintf(buf, "^%d", i)
+		}
+	}
+	if n > 1 {
+		return "(" + buf.String() + ")"
+	}
+	return buf.String()
+}
+
+// fieldPow calculates a^n by repeated squaring. Negative exponents are
+// calculated using the inverse of a.
+func fieldPow(f Field, a Coeff, n *big.Int) Coeff {
+	if n.Sign() < 0 {
+		a = f.Quo(f.One(), a)
+	}
+	rval := f.One()
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		rval = f.Mul(rval, rval)
+		if n.Bit(i) == 1 {
+			rval = f.Mul(rval, a)
+		}
+	}
+	return rval
+}
+
+// characteristic returns the characteristic of the field f.
+func characteristic(f Field) uint64 {
+	switch x := f.(type) {
+	case *PrimeField:
+		return x.p
+	case *ExtensionField:
+		return characteristic(x.base)
+	}
+	return 0
+}
+
+// pthRoot calculates the p-th root of a in a finite field of characteristic
+// p. The Frobenius map a -> a^p is an automorphism of order k in GF(p^k),
+// so the p-th root of a is a^(p^(k-1)).
+func pthRoot(f Field, a Coeff) Coeff {
+	ext, ok := f.(*ExtensionField)
+	if !ok {
+		return a
+	}
+	p := new(big.Int).SetUint64(characteristic(f))
+	return fieldPow(f, a, p.Exp(p, big.NewInt(int64(ext.Degree()-1)), nil))
+}
+
+// Elem is an expression holding a coefficient of a field other than the
+// rationals.
+type Elem struct {
+	F Field
+	C Coeff
+}
+
+func (e Elem) String() string {
+	return e.F.Format(e.C)
+}
+
+// coeffExpr converts a coefficient into an expression. Rational numbers are
+// returned as Num values.
+func coeffExpr(f Field, c Coeff) Expr {
+	if r, ok := c.(*big.Rat); ok && f == Rationals {
+		return Num{r}
+	}
+	return Elem{f, c}
+}
+
+// elemArith evaluates the arithmetic expression a op b if one of the
+// operands is a field element. It returns nil if the expression can not be
+// evaluated.
+func elemArith(op byte, a, b Expr) (Expr, error) {
+	var f Field
+	if x, ok := a.(Elem); ok {
+		f = x.F
+	} else if x, ok := b.(Elem); ok {
+		f = x.F
+	} else {
+		return nil, nil
+	}
+	if op == '^' {
+		n, ok := b.(Num)
+		if !ok || !n.IsInt() {
+			return nil, nil
+		}
+		x := a.(Elem)
+		if n.Sign() < 0 && f.IsZero(x.C) {
+			return nil, errors.New("division by zero")
+		}
+		return Elem{f, fieldPow(f, x.C, n.Num())}, nil
+	}
+	var args [2]Coeff
+	for i, e := range []Expr{a, b} {
+		switch x := e.(type) {
+		case Elem:
+			if x.F != f {
+				return nil, fmt.Errorf("incompatible fields %v and %v", x.F, f)
+			}
+			args[i] = x.C
+		case Num:
+			c, err := f.FromRat(x.Rat)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = c
+		default:
+			return nil, nil
+		}
+	}
+	switch op {
+	case '+':
+		return Elem{f, f.Add(args[0], args[1])}, nil
+	case '-':
+		return Elem{f, f.Sub(args[0], args[1])}, nil
+	case '*':
+		return Elem{f, f.Mul(args[0], args[1])}, nil
+	case '/':
+		if f.IsZero(args[1]) {
+			return nil, errors.New("division by zero")
+		}
+		return Elem{f, f.Quo(args[0], args[1])}, nil
+	}
+	return nil, nil
+}
+
+// collectField returns the field of the coefficients used in expr. All
+// field elements must belong to the same field, which must be def unless
+// def are the rationals. Expressions without field elements have
+// coefficients in def.
+func collectField(expr Expr, def Field) (Field, error) {
+	var (
+		field Field
+		err   error
+	)
+	var visit func(expr Expr)
+	visit = func(expr Expr) {
+		switch x := expr.(type) {
+		case Elem:
+			if field != nil && field != x.F {
+				err = fmt.Errorf("incompatible fields %v and %v", field, x.F)
+			}
+			field = x.F
+		case Add:
+			visit(x.A)
+			visit(x.B)
+		case Sub:
+			visit(x.A)
+			visit(x.B)
+		case Mul:
+			visit(x.A)
+			visit(x.B)
+		case Div:
+			visit(x.A)
+			visit(x.B)
+		case Pow:
+			visit(x.A)
+			visit(x.B)
+		case List:
+			for i := range x {
+				visit(x[i])
+			}
+		}
+	}
+	visit(expr)
+	if field == nil {
+		field = def
+	} else if err == nil && def != Rationals && def != field {
+		err = fmt.Errorf("incompatible fields %v and %v", field, def)
+	}
+	return field, err
+}
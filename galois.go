@@ -0,0 +1,151 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// galoisFields caches all finite fields created by NewGaloisField, so that
+// elements of equal fields can be combined.
+var galoisFields = make(map[[2]uint64]*ExtensionField)
+
+// NewGaloisField returns the finite field with p^k elements. The field is
+// constructed as an extension of the prime field modulo the smallest
+// primitive polynomial of degree k, therefore the adjoined root a generates
+// the multiplicative group of the field.
+func NewGaloisField(p uint64, k int) (*ExtensionField, error) {
+	if f, ok := galoisFields[[2]uint64{p, uint64(k)}]; ok {
+		return f, nil
+	}
+	base, err := NewPrimeField(p)
+	if err != nil {
+		return nil, err
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("invalid degree %d", k)
+	}
+	order := new(big.Int).Exp(new(big.Int).SetUint64(p), big.NewInt(int64(k)), nil)
+	if k > 1 && order.BitLen() > 32 {
+		return nil, fmt.Errorf("field GF(%d^%d) is too large", p, k)
+	}
+	group := new(big.Int).Sub(order, big.NewInt(1))
+	factors := primeFactors(group.Uint64())
+
+	// enumerate all monic polynomials of degree k in lexicographic order
+	m := make([]Coeff, k+1)
+	m[k] = base.One()
+	for n := uint64(0); n < group.Uint64()+1; n++ {
+		x := n
+		for i := 0; i < k; i++ {
+			m[i] = x % p
+			x /= p
+		}
+		if base.IsZero(m[0]) {
+			continue
+		}
+		if isPrimitive(base, m, group, factors) {
+			f, err := NewExtensionField(base, m, "a")
+			if err == nil {
+				galoisFields[[2]uint64{p, uint64(k)}] = f
+			}
+			return f, err
+		}
+	}
+	return nil, fmt.Errorf("no primitive polynomial found")
+}
+
+// isPrimitive reports whether the monic polynomial m of degree k over the
+// prime field is primitive, i.e. whether m is irreducible and x has order
+// p^k-1 modulo m.
+func isPrimitive(f *PrimeField, m []Coeff, group *big.Int, factors []uint64) bool {
+	if !isIrreducible(f, m) {
+		return false
+	}
+	x := []Coeff{f.Zero(), f.One()}
+	if upolyDegree(m) == 1 {
+		// the root of x - c must be a primitive root modulo p
+		x = []Coeff{f.Neg(m[0])}
+	}
+
+	one := []Coeff{f.One()}
+	if !upolyEqual(f, upolyPowMod(f, x, group, m), one) {
+		return false
+	}
+	for _, q := range factors {
+		e := new(big.Int).Quo(group, new(big.Int).SetUint64(q))
+		if upolyEqual(f, upolyPowMod(f, x, e, m), one) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIrreducible reports whether the polynomial m over the prime field is
+// irreducible with Rabin's test: m divides x^(p^k) - x and m is coprime to
+// x^(p^(k/q)) - x for every prime divisor q of its degree k.
+func isIrreducible(f *PrimeField, m []Coeff) bool {
+	k := upolyDegree(m)
+	if k < 1 {
+		return false
+	}
+	x := []Coeff{f.Zero(), f.One()}
+	p := new(big.Int).SetUint64(f.p)
+	for _, q := range primeFactors(uint64(k)) {
+		e := new(big.Int).Exp(p, big.NewInt(int64(k)/int64(q)), nil)
+		h := upolySub(f, upolyPowMod(f, x, e, m), x)
+		if g := upolyGCD(f, h, m); upolyDegree(g) > 0 {
+			return false
+		}
+	}
+	e := new(big.Int).Exp(p, big.NewInt(int64(k)), nil)
+	return len(upolySub(f, upolyPowMod(f, x, e, m), upolyMod(f, x, m))) == 0
+}
+
+// NewGaloisFieldModulus returns the finite field GF(p)[a]/(m) with p^k
+// elements, where k is the degree of the univariate polynomial m. The
+// coefficients of m are reduced modulo p and the result must be irreducible.
+// The adjoined root is printed using the variable of m.
+func NewGaloisFieldModulus(p uint64, m *Polynomial) (*ExtensionField, error) {
+	base, err := NewPrimeField(p)
+	if err != nil {
+		return nil, err
+	}
+	if m.field != Rationals || len(m.vars) != 1 {
+		return nil, fmt.Errorf("invalid modulus %v", m)
+	}
+	mq, err := m.mapCoeffs(base, func(c Coeff) (Coeff, error) {
+		return base.FromRat(c.(*big.Rat))
+	})
+	if err != nil {
+		return nil, err
+	}
+	modulus, err := upolyFromPolynomial(mq)
+	if err != nil {
+		return nil, err
+	}
+	if !isIrreducible(base, modulus) {
+		return nil, fmt.Errorf("modulus %v is not irreducible over %v", mq, base)
+	}
+	return NewExtensionField(base, modulus, m.vars[0])
+}
+
+// primeFactors returns the distinct prime factors of n using trial
+// division.
+func primeFactors(n uint64) []uint64 {
+	var factors []uint64
+	for q := uint64(2); q*q <= n; q++ {
+		if n%q == 0 {
+			factors = append(factors, q)
+			for n%q == 0 {
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
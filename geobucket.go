@@ -0,0 +1,88 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+// geobucket accumulates a sum of many polynomials. The sum is stored in
+// buckets of geometrically increasing sizes, the i-th bucket holds at most
+// 4^(i+1) items. Adding a short polynomial therefore only merges it with
+// the short buckets, while the leading monomial of the whole sum can still
+// be extracted efficiently. This turns long reduction chains, which would
+// merge a short reductor with the whole remainder over and over again,
+// into an almost linear process (see Yan, "The Geobucket Data Structure
+// for Polynomials", 1998).
+type geobucket struct {
+	proto   *Polynomial
+	buckets []*Polynomial
+}
+
+func newGeobucket(p *Polynomial) *geobucket {
+	g := &geobucket{proto: &Polynomial{vars: p.vars, order: p.order,
+		field: p.field}}
+	g.Add(p)
+	return g
+}
+
+func bucketSize(i int) int {
+	return 4 << (2 * uint(i))
+}
+
+// Add adds the polynomial p to the sum.
+func (g *geobucket) Add(p *Polynomial) {
+	i := 0
+	for bucketSize(i) < len(p.items) {
+		i++
+	}
+	for {
+		for len(g.buckets) <= i {
+			g.buckets = append(g.buckets, g.proto)
+		}
+		p = g.buckets[i].Add(p)
+		if len(p.items) <= bucketSize(i) {
+			g.buckets[i] = p
+			return
+		}
+		g.buckets[i] = g.proto
+		i++
+	}
+}
+
+// PopLead removes the leading monomial from the sum and returns it. The
+// second return value is false if the sum is zero.
+func (g *geobucket) PopLead() (Monomial, bool) {
+	f := g.proto.field
+	for {
+		max := -1
+		for i, b := range g.buckets {
+			if len(b.items) == 0 {
+				continue
+			}
+			if max < 0 || g.proto.order(g.buckets[max].items[0].T, b.items[0].T) {
+				max = i
+			}
+		}
+		if max < 0 {
+			return Monomial{}, false
+		}
+		lead := g.buckets[max].items[0]
+		c := f.Zero()
+		for i, b := range g.buckets {
+			if len(b.items) > 0 && termEqual(b.items[0].T, lead.T) {
+				c = f.Add(c, b.items[0].C)
+				g.buckets[i] = b.Remainder()
+			}
+		}
+		if !f.IsZero(c) {
+			return Monomial{c, lead.T}, true
+		}
+	}
+}
+
+// Polynomial returns the sum of all buckets.
+func (g *geobucket) Polynomial() *Polynomial {
+	rval := g.proto
+	for _, b := range g.buckets {
+		rval = rval.Add(b)
+	}
+	return rval
+}
@@ -20,8 +20,8 @@ import (
 	val Expr
 }
 
-%token <val> NUM ID
-%type <val> stmt expr items items2
+%token <val> NUM ID STRING
+%type <val> stmt expr item items items2
 
 %right '='
 %left '+'  '-'
@@ -39,6 +39,7 @@ all
 stmt
 	: expr { $$ = $1 }
 	| ID '=' expr { $$ = Assign{$1.(Ident), $3}}
+	| NUM '=' expr { yylex.Error(fmt.Sprintf("can not assign to %v", $1)) }
 	;
 
 expr
@@ -61,8 +62,13 @@ items
 	;
 
 items2
-	: expr { $$ = List{$1}}
-	| items2 ',' expr { $$ = append($1.(List), $3) }
+	: item { $$ = List{$1}}
+	| items2 ',' item { $$ = append($1.(List), $3) }
+	;
+
+item
+	: expr { $$ = $1 }
+	| STRING { $$ = $1 }
 	;
 
 %%
@@ -100,6 +106,22 @@ func (l *Lexer) Lex(lval *yySymType) int {
 			lval.val = Num{v}
 			l.pos = i
 			return NUM
+		case r == '"':
+			i := l.pos + n
+			for {
+				r, n = utf8.DecodeRuneInString(l.input[i:])
+				if n == 0 {
+					l.Error("unterminated string")
+					return 0
+				}
+				i += n
+				if r == '"' {
+					break
+				}
+			}
+			lval.val = Str(l.input[l.pos+1 : i-1])
+			l.pos = i
+			return STRING
 		case unicode.IsLetter(r):
 			i := l.pos + n
 			for {
@@ -109,8 +131,15 @@ func (l *Lexer) Lex(lval *yySymType) int {
 				}
 				i += n
 			}
-			lval.val = Ident(l.input[l.pos:i])
+			ident := l.input[l.pos:i]
 			l.pos = i
+			if ident == "i" {
+				// the imaginary unit is a literal like the numbers, so
+				// the name i is reserved
+				lval.val = Elem{GaussianRationals, GaussianRationals.Generator()}
+				return NUM
+			}
+			lval.val = Ident(ident)
 			return ID
 		default:
 			return int(r)
@@ -119,8 +148,13 @@ func (l *Lexer) Lex(lval *yySymType) int {
 	return 0
 }
 
+func init() {
+	// report the unexpected token, e.g. a string outside of an argument list
+	yyErrorVerbose = true
+}
+
 func (l *Lexer) Error(s string) {
-	l.err = SyntaxError(s)
+	l.err = SyntaxError(strings.TrimPrefix(s, "syntax error: "))
 }
 
 type SyntaxError string
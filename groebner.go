@@ -0,0 +1,183 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// groebnerAlgorithms contains all algorithms which can be selected with the
+// second argument of the groebner builtin.
+var groebnerAlgorithms = map[string]func([]*Polynomial) ([]*Polynomial, error){
+	"buchberger": func(fns []*Polynomial) ([]*Polynomial, error) {
+		return Groebner(fns), nil
+	},
+	"modular": GroebnerModular,
+	"f4": func(fns []*Polynomial) ([]*Polynomial, error) {
+		return GroebnerF4(fns), nil
+	},
+	"gvw": func(fns []*Polynomial) ([]*Polynomial, error) {
+		return GroebnerSignature(fns), nil
+	},
+}
+
+// critPair is a pair of basis elements whose S-polynomial still has to be
+// reduced.
+type critPair struct {
+	i, j int
+	lcm  Term
+}
+
+// Groebner calculates the reduced Gröbner basis of the ideal generated by
+// fns using Buchberger's algorithm. Pairs are selected by the normal
+// strategy and useless pairs are detected with Buchberger's product and
+// chain criterion.
+func Groebner(fns []*Polynomial) []*Polynomial {
+	return groebnerBuchberger(fns, &GroebnerStats{})
+}
+
+func groebnerBuchberger(fns []*Polynomial, stats *GroebnerStats) []*Polynomial {
+	var (
+		basis []*Polynomial
+		pairs []critPair
+	)
+	add := func(f *Polynomial) {
+		basis = append(basis, f.Monic())
+		k := len(basis) - 1
+		for i := 0; i < k; i++ {
+			pairs = append(pairs, critPair{i, k,
+				termLCM(basis[i].items[0].T, basis[k].items[0].T)})
+		}
+	}
+	for _, f := range fns {
+		if !f.IsZero() {
+			add(f)
+		}
+	}
+	for len(pairs) > 0 {
+		sel := 0
+		for k := range pairs {
+			if basis[0].order(pairs[k].lcm, pairs[sel].lcm) {
+				sel = k
+			}
+		}
+		pair := pairs[sel]
+		pairs = append(pairs[:sel], pairs[sel+1:]...)
+		stats.Pairs++
+		if skipPair(basis, pairs, pair) {
+			stats.Skipped++
+			continue
+		}
+		stats.Reductions++
+		h := SPolynomial(basis[pair.i], basis[pair.j]).NormalForm(basis)
+		if h.IsZero() {
+			stats.ZeroReductions++
+		} else {
+			add(h)
+		}
+	}
+	return reduceBasis(basis)
+}
+
+// skipPair reports whether the S-polynomial of the pair is known to reduce
+// to zero without calculating it.
+func skipPair(basis []*Polynomial, pairs []critPair, pair critPair) bool {
+	a, b := basis[pair.i].items[0].T, basis[pair.j].items[0].T
+	if termCoprime(a, b) {
+		return true
+	}
+	pending := func(i, j int) bool {
+		if i > j {
+			i, j = j, i
+		}
+		for k := range pairs {
+			if pairs[k].i == i && pairs[k].j == j {
+				return true
+			}
+		}
+		return false
+	}
+	for k := range basis {
+		if k == pair.i || k == pair.j {
+			continue
+		}
+		if termDivides(basis[k].items[0].T, pair.lcm) &&
+			!pending(pair.i, k) && !pending(pair.j, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// reduceBasis turns a Gröbner basis into the unique reduced Gröbner basis
+// by removing redundant elements and reducing the remaining ones with
+// each other. The result is sorted by leading terms in descending order.
+func reduceBasis(basis []*Polynomial) []*Polynomial {
+	var minimal []*Polynomial
+	for i, f := range basis {
+		redundant := false
+		for j, g := range basis {
+			if i == j {
+				continue
+			}
+			if termDivides(g.items[0].T, f.items[0].T) &&
+				(!termEqual(g.items[0].T, f.items[0].T) || j < i) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			minimal = append(minimal, f.Monic())
+		}
+	}
+	rval := make([]*Polynomial, len(minimal))
+	for i := range minimal {
+		others := make([]*Polynomial, 0, len(minimal)-1)
+		others = append(others, minimal[:i]...)
+		others = append(others, minimal[i+1:]...)
+		lm := minimal[i].LM()
+		rval[i] = lm.Add(minimal[i].Remainder().NormalForm(others))
+	}
+	sort.Sort(basisSorter(rval))
+	return rval
+}
+
+type basisSorter []*Polynomial
+
+func (s basisSorter) Less(i, j int) bool {
+	return s[i].order(s[j].items[0].T, s[i].items[0].T)
+}
+
+func (s basisSorter) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s basisSorter) Len() int {
+	return len(s)
+}
+
+// isGroebner reports whether fns is a Gröbner basis by checking that all
+// S-polynomials reduce to zero.
+func isGroebner(fns []*Polynomial) bool {
+	for i := range fns {
+		for j := i + 1; j < len(fns); j++ {
+			if termCoprime(fns[i].items[0].T, fns[j].items[0].T) {
+				continue
+			}
+			if !SPolynomial(fns[i], fns[j]).NormalForm(fns).IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func groebnerByName(name string) (func([]*Polynomial) ([]*Polynomial, error), error) {
+	alg, ok := groebnerAlgorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q", name)
+	}
+	return alg, nil
+}
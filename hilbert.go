@@ -0,0 +1,195 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// HilbertSeries is the Hilbert series num(t)/(1-t)^dim of R/LT(I), where
+// LT(I) is the leading term ideal of I with respect to a degree compatible
+// term order. For homogeneous ideals it agrees with the Hilbert series of
+// R/I, otherwise it describes the dimension of the polynomials of degree
+// at most s modulo I by its partial sums.
+type HilbertSeries struct {
+	num []Coeff
+	dim int
+}
+
+// NewHilbertSeries calculates the Hilbert series of R/LT(I) using the
+// reduced Gröbner basis of I with respect to the degree reverse
+// lexicographical order.
+func NewHilbertSeries(I *Ideal) (*HilbertSeries, error) {
+	n := len(I.vars)
+	var gens []*Polynomial
+	for _, f := range I.gens {
+		gens = append(gens, f.withOrder(GrevlexTermOrder))
+	}
+	var mons [][]int
+	if len(gens) > 0 {
+		for _, g := range Groebner(gens) {
+			lt := g.LPP().Support(g.vars)[0]
+			m := make([]int, n)
+			for i, e := range lt {
+				if !e.IsInt() || e.Sign() < 0 || e.Num().BitLen() > 24 {
+					return nil, fmt.Errorf("hilbert series requires non-negative integer exponents, got %v", g.LPP())
+				}
+				m[i] = int(e.Num().Int64())
+			}
+			mons = append(mons, m)
+		}
+	}
+	hs := &HilbertSeries{num: hilbertNumerator(mons), dim: n}
+	if len(hs.num) == 0 {
+		hs.dim = -1
+		return hs, nil
+	}
+	// cancel common factors 1-t, N(1) = 0 if and only if 1-t divides N
+	oneMinusT := []Coeff{big.NewRat(1, 1), big.NewRat(-1, 1)}
+	for hs.dim > 0 {
+		q, r := upolyDivMod(Rationals, hs.num, oneMinusT)
+		if len(r) > 0 {
+			break
+		}
+		hs.num, hs.dim = q, hs.dim-1
+	}
+	return hs, nil
+}
+
+func (hs *HilbertSeries) String() string {
+	num := formatUpoly(Rationals, hs.num, "t")
+	switch hs.dim {
+	case -1, 0:
+		return num
+	case 1:
+		return num + "/(1 - t)"
+	}
+	return fmt.Sprintf("%s/(1 - t)^%d", num, hs.dim)
+}
+
+// Dim returns the Krull dimension of R/I. The unit ideal has dimension -1.
+func (hs *HilbertSeries) Dim() int {
+	return hs.dim
+}
+
+// Degree returns the degree (multiplicity) of R/I, which is the value of
+// the reduced numerator at t = 1.
+func (hs *HilbertSeries) Degree() *big.Rat {
+	deg := new(big.Rat)
+	for _, c := range hs.num {
+		deg.Add(deg, c.(*big.Rat))
+	}
+	return deg
+}
+
+// Polynomial returns the Hilbert polynomial in the variable s, which agrees
+// with the Hilbert function for large degrees s. The coefficient h_k*t^k of
+// the numerator contributes h_k * binomial(s - k + dim - 1, dim - 1).
+func (hs *HilbertSeries) Polynomial() *Polynomial {
+	var hp []Coeff
+	if hs.dim > 0 {
+		for k, h := range hs.num {
+			term := []Coeff{h}
+			for j := 1; j < hs.dim; j++ {
+				// (s - k + j) / j
+				f := []Coeff{big.NewRat(int64(j-k), int64(j)), big.NewRat(1, int64(j))}
+				term = upolyMul(Rationals, term, f)
+			}
+			hp = upolyAdd(Rationals, hp, term)
+		}
+	}
+	return upolyToPolynomial(Rationals, hp, []string{"s"}, 0, LexTermOrder)
+}
+
+// hilbertNumerator calculates the numerator N(t) of the Hilbert series
+// N(t)/(1-t)^n of R/M for the monomial ideal M generated by the exponent
+// vectors mons. It uses the recursion N(M + <m>) = N(M) - t^deg(m)*N(M : m)
+// until the generators are pairwise coprime, in which case the numerator
+// is the product of the factors 1 - t^deg(m).
+func hilbertNumerator(mons [][]int) []Coeff {
+	mons = minimalMonomials(mons)
+	coprime := true
+	for i := 0; i < len(mons) && coprime; i++ {
+		for j := i + 1; j < len(mons) && coprime; j++ {
+			for k := range mons[i] {
+				if mons[i][k] > 0 && mons[j][k] > 0 {
+					coprime = false
+					break
+				}
+			}
+		}
+	}
+	if coprime {
+		num := []Coeff{big.NewRat(1, 1)}
+		for _, m := range mons {
+			num = upolyMul(Rationals, num, hilbertFactor(m))
+		}
+		return num
+	}
+	last := mons[len(mons)-1]
+	rest := mons[:len(mons)-1]
+	colon := make([][]int, len(rest))
+	for i, m := range rest {
+		colon[i] = make([]int, len(m))
+		for k := range m {
+			if m[k] > last[k] {
+				colon[i][k] = m[k] - last[k]
+			}
+		}
+	}
+	shift := make([]Coeff, monomialDegree(last)+1)
+	for i := range shift {
+		shift[i] = new(big.Rat)
+	}
+	shift[len(shift)-1] = big.NewRat(1, 1)
+	return upolySub(Rationals, hilbertNumerator(rest),
+		upolyMul(Rationals, shift, hilbertNumerator(colon)))
+}
+
+// hilbertFactor returns 1 - t^deg(m).
+func hilbertFactor(m []int) []Coeff {
+	f := make([]Coeff, monomialDegree(m)+1)
+	for i := range f {
+		f[i] = new(big.Rat)
+	}
+	f[0] = big.NewRat(1, 1)
+	f[len(f)-1] = new(big.Rat).Sub(f[len(f)-1].(*big.Rat), big.NewRat(1, 1))
+	return upolyTrim(Rationals, f)
+}
+
+func monomialDegree(m []int) int {
+	d := 0
+	for _, e := range m {
+		d += e
+	}
+	return d
+}
+
+// minimalMonomials removes all exponent vectors which are divisible by
+// another one and duplicates.
+func minimalMonomials(mons [][]int) [][]int {
+	divides := func(a, b []int) bool {
+		for k := range a {
+			if a[k] > b[k] {
+				return false
+			}
+		}
+		return true
+	}
+	var rval [][]int
+	for i, m := range mons {
+		redundant := false
+		for j, d := range mons {
+			if i != j && divides(d, m) && (!divides(m, d) || j < i) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			rval = append(rval, m)
+		}
+	}
+	return rval
+}
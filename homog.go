@@ -0,0 +1,194 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// termWeight returns the weighted degree of t, the sum of the exponents
+// multiplied with the weights of the variables. If w is nil, every variable
+// has weight 1. The second return value is false if t has non-integer
+// exponents.
+func termWeight(t Term, w []int64) (int64, bool) {
+	var deg int64
+	for i := 0; i < t.Len(); i++ {
+		e, ok := t.Int(i)
+		if !ok {
+			return 0, false
+		}
+		if w == nil {
+			deg += int64(e)
+		} else {
+			deg += int64(e) * w[i]
+		}
+	}
+	return deg, true
+}
+
+// weightedDegree returns the maximal weighted degree of the terms of p.
+func weightedDegree(p *Polynomial, w []int64) (int64, bool) {
+	var deg int64
+	for i, m := range p.items {
+		d, ok := termWeight(m.T, w)
+		if !ok {
+			return 0, false
+		}
+		if i == 0 || d > deg {
+			deg = d
+		}
+	}
+	return deg, true
+}
+
+// isHomogeneous reports whether all terms of p have the same weighted
+// degree, which is returned as well. The zero polynomial is homogeneous of
+// degree 0.
+func isHomogeneous(p *Polynomial, w []int64) (int64, bool) {
+	var deg int64
+	for i, m := range p.items {
+		d, ok := termWeight(m.T, w)
+		if !ok || (i > 0 && d != deg) {
+			return 0, false
+		}
+		deg = d
+	}
+	return deg, true
+}
+
+// homogeneousDegree returns the total degree of p, the second return
+// value is false if p is not homogeneous with respect to the standard
+// grading.
+func homogeneousDegree(p *Polynomial) (int, bool) {
+	d, ok := isHomogeneous(p, nil)
+	return int(d), ok
+}
+
+// Homogenize multiplies every term of p with the power of the new variable
+// h, which raises its weighted degree to the weighted degree of p. The
+// variable h has weight 1.
+func (p *Polynomial) Homogenize(h string, w []int64) (*Polynomial, error) {
+	for _, v := range p.vars {
+		if v == h {
+			return nil, fmt.Errorf("variable %s already occurs in %v", h, p)
+		}
+	}
+	deg, ok := weightedDegree(p, w)
+	if !ok {
+		return nil, fmt.Errorf("homogenize requires integer exponents")
+	}
+	vars := append(append([]string{}, p.vars...), h)
+	sort.Strings(vars)
+	q, err := p.embed(vars, p.order)
+	if err != nil {
+		return nil, err
+	}
+	k := sort.SearchStrings(vars, h)
+	var wq []int64
+	if w != nil {
+		wq = append(append(append(wq, w[:k]...), 1), w[k:]...)
+	}
+	rval := &Polynomial{vars: vars, order: p.order, field: p.field}
+	for _, m := range q.items {
+		d, _ := termWeight(m.T, wq)
+		rval.items = append(rval.items,
+			Monomial{m.C, m.T.addExp(k, big.NewRat(deg-d, 1))})
+	}
+	rval.normalize()
+	return rval, nil
+}
+
+// Dehomogenize substitutes 1 for the variable h.
+func (p *Polynomial) Dehomogenize(h string) *Polynomial {
+	var vars []string
+	var idx []int
+	for i, v := range p.vars {
+		if v != h {
+			vars = append(vars, v)
+			idx = append(idx, i)
+		}
+	}
+	if len(vars) == len(p.vars) {
+		return p
+	}
+	rval := &Polynomial{vars: vars, order: p.order, field: p.field}
+	for _, m := range p.items {
+		rval.items = append(rval.items, Monomial{m.C, m.T.remap(idx)})
+	}
+	rval.normalize()
+	return rval
+}
+
+// Homogenize returns the homogenization of I, the ideal of the projective
+// closure of its zero set. It is generated by the homogenized elements of a
+// Gröbner basis with respect to a degree compatible order, i.e. the degree
+// reverse lexicographical order refined by the weights.
+func (I *Ideal) Homogenize(h string, w []int64) (*Ideal, error) {
+	rval := &Ideal{order: I.order, field: I.field}
+	if len(I.gens) == 0 {
+		rval.vars = append(append([]string{}, I.vars...), h)
+		sort.Strings(rval.vars)
+		return rval, nil
+	}
+	for _, g := range I.gradedBasis(w) {
+		f, err := g.withOrder(I.order).Homogenize(h, w)
+		if err != nil {
+			return nil, err
+		}
+		rval.vars, rval.gens = f.vars, append(rval.gens, f)
+	}
+	return rval, nil
+}
+
+// IsHomogeneous reports whether I is generated by homogeneous polynomials,
+// which is the case if and only if its reduced Gröbner basis with respect
+// to a degree compatible order consists of homogeneous polynomials.
+func (I *Ideal) IsHomogeneous(w []int64) bool {
+	for _, g := range I.gradedBasis(w) {
+		if _, ok := isHomogeneous(g, w); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// gradedBasis returns the reduced Gröbner basis of I with respect to the
+// degree reverse lexicographical order refined by the weights w.
+func (I *Ideal) gradedBasis(w []int64) []*Polynomial {
+	if len(I.gens) == 0 {
+		return nil
+	}
+	m, _ := namedOrderMatrix("grevlex", len(I.vars))
+	weights := make([]*big.Rat, len(I.vars))
+	for i := range weights {
+		weights[i] = big.NewRat(1, 1)
+		if w != nil {
+			weights[i].SetInt64(w[i])
+		}
+	}
+	order := m.refine(weights).TermOrder()
+	gens := make([]*Polynomial, len(I.gens))
+	for i, f := range I.gens {
+		gens[i] = f.withOrder(order)
+	}
+	return Groebner(gens)
+}
+
+// Dehomogenize substitutes 1 for the variable h in all generators.
+func (I *Ideal) Dehomogenize(h string) *Ideal {
+	rval := &Ideal{order: I.order, field: I.field}
+	for _, v := range I.vars {
+		if v != h {
+			rval.vars = append(rval.vars, v)
+		}
+	}
+	for _, f := range I.gens {
+		if g := f.Dehomogenize(h); !g.IsZero() {
+			rval.gens = append(rval.gens, g)
+		}
+	}
+	return rval
+}
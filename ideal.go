@@ -0,0 +1,329 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Ideal is an ideal of a polynomial ring given by a list of generators,
+// which share the same variables, term order and coefficient field.
+type Ideal struct {
+	vars  []string
+	order TermOrder
+	field Field
+	gens  []*Polynomial
+}
+
+// NewIdeal creates the ideal generated by fns. All polynomials must use the
+// same variables, e.g. as returned by convertPolynomials.
+func NewIdeal(fns []*Polynomial) *Ideal {
+	I := &Ideal{order: LexTermOrder, field: Rationals}
+	if len(fns) > 0 {
+		I.vars, I.order, I.field = fns[0].vars, fns[0].order, fns[0].field
+	}
+	for _, f := range fns {
+		if !f.IsZero() {
+			I.gens = append(I.gens, f)
+		}
+	}
+	return I
+}
+
+func (I *Ideal) String() string {
+	if len(I.gens) == 0 {
+		return "<0>"
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("<")
+	for i, f := range I.gens {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(f.String())
+	}
+	buf.WriteString(">")
+	return buf.String()
+}
+
+// Basis returns the reduced Gröbner basis of the ideal.
+func (I *Ideal) Basis() []*Polynomial {
+	if len(I.gens) == 0 {
+		return nil
+	}
+	return Groebner(I.gens)
+}
+
+// unit returns the polynomial 1 in the ring of I.
+func (I *Ideal) unit() *Polynomial {
+	return &Polynomial{vars: I.vars, order: I.order, field: I.field,
+		items: []Monomial{{I.field.One(), NewTerm(len(I.vars))}}}
+}
+
+// embed rewrites the generators of I in the variables vars.
+func (I *Ideal) embed(vars []string) (*Ideal, error) {
+	rval := &Ideal{vars: vars, order: I.order, field: I.field}
+	for _, f := range I.gens {
+		g, err := f.embed(vars, I.order)
+		if err != nil {
+			return nil, err
+		}
+		rval.gens = append(rval.gens, g)
+	}
+	return rval, nil
+}
+
+// commonRing rewrites both ideals in the union of their variables. The
+// term order is taken from the first ideal.
+func commonRing(a, b *Ideal) (*Ideal, *Ideal, error) {
+	if len(a.gens) > 0 && len(b.gens) > 0 && a.field != b.field {
+		return nil, nil, fmt.Errorf("incompatible fields %v and %v", a.field, b.field)
+	}
+	seen := make(map[string]bool)
+	var vars []string
+	for _, v := range append(append([]string{}, a.vars...), b.vars...) {
+		if !seen[v] {
+			seen[v] = true
+			vars = append(vars, v)
+		}
+	}
+	sort.Strings(vars)
+	if len(a.gens) == 0 {
+		a = &Ideal{order: a.order, field: b.field}
+	}
+	if len(b.gens) == 0 {
+		b = &Ideal{order: b.order, field: a.field}
+	}
+	b = &Ideal{vars: b.vars, order: a.order, field: b.field, gens: b.gens}
+	a, err := a.embed(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err = b.embed(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+// IdealSum returns the ideal generated by the generators of both ideals.
+func IdealSum(a, b *Ideal) (*Ideal, error) {
+	a, b, err := commonRing(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return &Ideal{vars: a.vars, order: a.order, field: a.field,
+		gens: append(append([]*Polynomial{}, a.gens...), b.gens...)}, nil
+}
+
+// IdealProduct returns the ideal generated by all products f*g of the
+// generators f of a and g of b.
+func IdealProduct(a, b *Ideal) (*Ideal, error) {
+	a, b, err := commonRing(a, b)
+	if err != nil {
+		return nil, err
+	}
+	rval := &Ideal{vars: a.vars, order: a.order, field: a.field}
+	for _, f := range a.gens {
+		for _, g := range b.gens {
+			rval.gens = append(rval.gens, f.Mul(g))
+		}
+	}
+	return rval, nil
+}
+
+// Intersect calculates the intersection of both ideals by eliminating t
+// from t*a + (1-t)*b.
+func Intersect(a, b *Ideal) (*Ideal, error) {
+	a, b, err := commonRing(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.gens) == 0 || len(b.gens) == 0 {
+		return &Ideal{vars: a.vars, order: a.order, field: a.field}, nil
+	}
+	ext := newElimination(a, 1)
+	t := ext.variable(0)
+	one := ext.unit()
+	var fns []*Polynomial
+	for _, f := range a.gens {
+		g, err := ext.lift(f)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, t.Mul(g))
+	}
+	for _, f := range b.gens {
+		g, err := ext.lift(f)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, one.Sub(t).Mul(g))
+	}
+	return ext.eliminate(fns), nil
+}
+
+// Quotient calculates the ideal quotient a : b, which consists of all
+// polynomials f with f*b contained in a. It is the intersection of the
+// quotients a : g for all generators g of b, and a : g is obtained by
+// dividing the generators of the intersection of a and <g> by g.
+func Quotient(a, b *Ideal) (*Ideal, error) {
+	a, b, err := commonRing(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var rval *Ideal
+	for _, g := range b.gens {
+		c, err := Intersect(a, NewIdeal([]*Polynomial{g}))
+		if err != nil {
+			return nil, err
+		}
+		q := &Ideal{vars: a.vars, order: a.order, field: a.field}
+		for _, h := range c.gens {
+			quo, _ := h.divide([]*Polynomial{g})
+			q.gens = append(q.gens, quo[0])
+		}
+		if rval == nil {
+			rval = q
+		} else if rval, err = Intersect(rval, q); err != nil {
+			return nil, err
+		}
+	}
+	if rval == nil {
+		return NewIdeal([]*Polynomial{a.unit()}), nil
+	}
+	return rval, nil
+}
+
+// Saturate calculates the saturation a : f^∞, the union of the quotients
+// a : f^k, by eliminating t from a + <1 - t*f>.
+func Saturate(a *Ideal, f *Polynomial) (*Ideal, error) {
+	a, b, err := commonRing(a, NewIdeal([]*Polynomial{f}))
+	if err != nil {
+		return nil, err
+	}
+	if len(b.gens) == 0 {
+		return NewIdeal([]*Polynomial{a.unit()}), nil
+	}
+	ext := newElimination(a, 1)
+	f, err = ext.lift(b.gens[0])
+	if err != nil {
+		return nil, err
+	}
+	fns := []*Polynomial{ext.unit().Sub(ext.variable(0).Mul(f))}
+	for _, g := range a.gens {
+		if f, err = ext.lift(g); err != nil {
+			return nil, err
+		}
+		fns = append(fns, f)
+	}
+	return ext.eliminate(fns), nil
+}
+
+// elimination is a polynomial ring with k auxiliary variables, which are
+// larger than all variables of the ideal I.
+type elimination struct {
+	I     *Ideal
+	k     int
+	vars  []string
+	order TermOrder
+}
+
+func newElimination(I *Ideal, k int) *elimination {
+	e := &elimination{I: I, k: k, order: eliminationOrder(k, I.order)}
+	for i := 0; i < k; i++ {
+		// the names can not clash with user variables
+		e.vars = append(e.vars, fmt.Sprintf("@t%d", i))
+	}
+	e.vars = append(e.vars, I.vars...)
+	return e
+}
+
+// variable returns the i-th auxiliary variable.
+func (e *elimination) variable(i int) *Polynomial {
+	t := NewTerm(len(e.vars)).addExp(i, big.NewRat(1, 1))
+	return &Polynomial{vars: e.vars, order: e.order, field: e.I.field,
+		items: []Monomial{{e.I.field.One(), t}}}
+}
+
+func (e *elimination) unit() *Polynomial {
+	return &Polynomial{vars: e.vars, order: e.order, field: e.I.field,
+		items: []Monomial{{e.I.field.One(), NewTerm(len(e.vars))}}}
+}
+
+// lift rewrites a polynomial of the ideal's ring in the extended ring.
+func (e *elimination) lift(f *Polynomial) (*Polynomial, error) {
+	return f.embed(e.vars, e.order)
+}
+
+// eliminate returns the intersection of the ideal generated by fns with
+// the ring of I. It consists of the elements of the Gröbner basis with
+// respect to the elimination order, which do not contain any auxiliary
+// variables.
+func (e *elimination) eliminate(fns []*Polynomial) *Ideal {
+	rval := &Ideal{vars: e.I.vars, order: e.I.order, field: e.I.field}
+	idx := make([]int, len(e.I.vars))
+	for i := range idx {
+		idx[i] = e.k + i
+	}
+	for _, g := range Groebner(fns) {
+		free := true
+		for i := 0; i < e.k && free; i++ {
+			free = g.items[0].T.Sign(i) == 0
+		}
+		if !free {
+			continue
+		}
+		h := &Polynomial{vars: e.I.vars, order: e.I.order, field: e.I.field}
+		h.items = make([]Monomial, len(g.items))
+		for i, m := range g.items {
+			h.items[i] = Monomial{m.C, m.T.remap(idx)}
+		}
+		h.normalize()
+		rval.gens = append(rval.gens, h)
+	}
+	rval.gens = reduceBasis(rval.gens)
+	return rval
+}
+
+// eliminationOrder returns a block order in which every term containing
+// one of the first k variables is larger than all terms without them. The
+// first k variables are compared by their total degree and lexicographic
+// afterwards, ties are broken by order on the remaining variables.
+func eliminationOrder(k int, order TermOrder) TermOrder {
+	return func(a, b Term) bool {
+		if a.compact() && b.compact() {
+			var da, db int64
+			for i := 0; i < k; i++ {
+				da += int64(a.exp[i])
+				db += int64(b.exp[i])
+			}
+			if da != db {
+				return da < db
+			}
+		} else {
+			da, db := new(big.Rat), new(big.Rat)
+			for i := 0; i < k; i++ {
+				da.Add(da, a.Exp(i))
+				db.Add(db, b.Exp(i))
+			}
+			if x := da.Cmp(db); x != 0 {
+				return x < 0
+			}
+		}
+		for i := 0; i < k; i++ {
+			if x := termCmp(a, b, i); x != 0 {
+				return x < 0
+			}
+		}
+		idx := make([]int, a.Len()-k)
+		for i := range idx {
+			idx[i] = k + i
+		}
+		return order(a.remap(idx), b.remap(idx))
+	}
+}
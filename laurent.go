@@ -0,0 +1,124 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ExponentMode selects the exponents which are allowed in the terms of
+// polynomials.
+type ExponentMode int
+
+const (
+	// PolynomialMode only allows non-negative exponents.
+	PolynomialMode ExponentMode = iota
+	// LaurentMode allows negative exponents, i.e. the polynomials are
+	// elements of the ring of Laurent polynomials, in which every term is a
+	// unit and polynomials can be divided by terms.
+	LaurentMode
+	// PuiseuxPolynomialMode allows arbitrary rational exponents. Terms are
+	// still multiplied by adding and divided by subtracting their exponents,
+	// but there is no term order which is a well-ordering on these terms,
+	// so reductions need not terminate.
+	PuiseuxPolynomialMode
+)
+
+var exponentModes = map[string]ExponentMode{
+	"polynomial": PolynomialMode,
+	"laurent":    LaurentMode,
+	"puiseux":    PuiseuxPolynomialMode,
+}
+
+func (m ExponentMode) String() string {
+	switch m {
+	case LaurentMode:
+		return "laurent"
+	case PuiseuxPolynomialMode:
+		return "puiseux"
+	}
+	return "polynomial"
+}
+
+// checkTerm returns an error if the exponents of t are not allowed in the
+// given mode.
+func checkTerm(t Term, vars []string, mode ExponentMode) error {
+	if mode == PuiseuxPolynomialMode {
+		return nil
+	}
+	for i := range vars {
+		if e, ok := t.Int(i); ok {
+			if e < 0 && mode != LaurentMode {
+				return fmt.Errorf("negative exponent in %s^%d, use mode(laurent) for Laurent polynomials",
+					vars[i], e)
+			}
+			continue
+		}
+		e := t.Exp(i)
+		if !e.IsInt() {
+			return fmt.Errorf("non-integer exponent in %s^%s, use mode(puiseux) for Puiseux polynomials",
+				vars[i], e.RatString())
+		}
+		if e.Sign() < 0 && mode != LaurentMode {
+			return fmt.Errorf("negative exponent in %s^%s, use mode(laurent) for Laurent polynomials",
+				vars[i], e.RatString())
+		}
+	}
+	return nil
+}
+
+// denominator returns the smallest term t, such that t*p has only
+// non-negative exponents.
+func (p *Polynomial) denominator() Term {
+	t := NewTerm(len(p.vars))
+	for i := range p.vars {
+		min := new(big.Rat)
+		for _, m := range p.items {
+			if e := m.T.Exp(i); e.Cmp(min) < 0 {
+				min = e
+			}
+		}
+		t = t.addExp(i, new(big.Rat).Neg(min))
+	}
+	return t
+}
+
+// Contract calculates the contraction of the ideal generated by the Laurent
+// polynomials fns, i.e. the ideal of all polynomials with non-negative
+// exponents in it. The polynomials are multiplied with their denominators,
+// which are units, and the ideal is saturated with respect to the product
+// of all variables afterwards. If the Laurent polynomials are binomials,
+// the contraction is a toric ideal.
+func Contract(fns []*Polynomial) (*Ideal, error) {
+	if len(fns) == 0 {
+		return nil, errors.New("contract requires at least one polynomial")
+	}
+	proto := fns[0]
+	gens := make([]*Polynomial, len(fns))
+	for i, f := range fns {
+		gens[i] = f.MulMonomial(f.field.One(), f.denominator())
+	}
+	prod := &Polynomial{vars: proto.vars, order: proto.order, field: proto.field,
+		items: []Monomial{{proto.field.One(), NewTerm(len(proto.vars))}}}
+	for i := range proto.vars {
+		prod.items[0].T = prod.items[0].T.addExp(i, ratOne)
+	}
+	return Saturate(NewIdeal(gens), prod)
+}
+
+// contractGenerators returns generators of the contraction of the ideal
+// generated by the Laurent polynomials fns. The algorithms for ideals of
+// polynomials compute the ideal of Laurent polynomials with them.
+func contractGenerators(fns []*Polynomial) ([]*Polynomial, error) {
+	if len(fns) == 0 {
+		return fns, nil
+	}
+	I, err := Contract(fns)
+	if err != nil {
+		return nil, err
+	}
+	return I.gens, nil
+}
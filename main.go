@@ -9,10 +9,15 @@ import (
 	"math/big"
 	"os"
 	"reflect"
+	"sort"
 )
 
 type Bruno struct {
 	globals map[string]interface{}
+	// mode selects the allowed exponents of polynomials
+	mode ExponentMode
+	// field is the coefficient field of polynomials without field elements
+	field Field
 }
 
 func NewBruno() *Bruno {
@@ -22,6 +27,8 @@ func NewBruno() *Bruno {
 }
 
 func (b *Bruno) reset() {
+	b.mode = PolynomialMode
+	b.field = Rationals
 	b.globals = map[string]interface{}{
 		"quit": func() {
 			fmt.Println("Bye.")
@@ -30,8 +37,25 @@ func (b *Bruno) reset() {
 		"reset": func() {
 			b.reset()
 		},
+		"mode": func(opts ...Expr) (Expr, error) {
+			if len(opts) > 1 {
+				return nil, fmt.Errorf("invalid number of args. expected at most 1, got %d", len(opts))
+			}
+			if len(opts) == 1 {
+				name, err := convertName(opts[0])
+				if err != nil {
+					return nil, err
+				}
+				mode, ok := exponentModes[name]
+				if !ok {
+					return nil, fmt.Errorf("unknown mode %q", name)
+				}
+				b.mode = mode
+			}
+			return Ident(b.mode.String()), nil
+		},
 		"p": func(expr Expr) (Expr, error) {
-			return NewPolynomial(expr)
+			return NewPolynomial(expr, b.field, b.mode)
 		},
 		"multicoeff": func(p *Polynomial, vars, exp Expr) (Expr, error) {
 			varlist, err := convertVars(vars)
@@ -60,7 +84,7 @@ func (b *Bruno) reset() {
 			varlist := term.vars
 			explist := make([]Num, len(term.vars))
 			for i := range explist {
-				explist[i] = Num{&term.items[0].T[i]}
+				explist[i] = Num{term.items[0].T.Exp(i)}
 			}
 			return p.MultiCoeff(varlist, explist), nil
 		},
@@ -91,6 +115,11 @@ func (b *Bruno) reset() {
 			p.normalize()
 			return p
 		},
+		"grevlexorder": func(p *Polynomial) Expr {
+			p.order = GrevlexTermOrder
+			p.normalize()
+			return p
+		},
 		"lpp": func(p *Polynomial) Expr {
 			return p.LPP()
 		},
@@ -101,25 +130,25 @@ func (b *Bruno) reset() {
 			return p.LM()
 		},
 		"higher": func(p *Polynomial, term Expr) (Expr, error) {
-			t, err := convertTerm(p, term)
+			t, err := convertTerm(p, term, b.mode)
 			if err != nil {
 				return nil, err
 			}
 			return p.Higher(t), nil
 		},
 		"lower": func(p *Polynomial, term Expr) (Expr, error) {
-			t, err := convertTerm(p, term)
+			t, err := convertTerm(p, term, b.mode)
 			if err != nil {
 				return nil, err
 			}
 			return p.Lower(t), nil
 		},
 		"between": func(p *Polynomial, term1, term2 Expr) (Expr, error) {
-			t1, err := convertTerm(p, term1)
+			t1, err := convertTerm(p, term1, b.mode)
 			if err != nil {
 				return nil, err
 			}
-			t2, err := convertTerm(p, term2)
+			t2, err := convertTerm(p, term2, b.mode)
 			if err != nil {
 				return nil, err
 			}
@@ -129,57 +158,596 @@ func (b *Bruno) reset() {
 			return p.Remainder()
 		},
 		"reduceterm": func(p *Polynomial, fe Expr, term Expr) (Expr, error) {
-			f := &Polynomial{vars: p.vars, order: p.order}
-			if err := f.convert(fe); err != nil {
+			f := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+			if err := f.convert(fe, b.mode); err != nil {
 				return nil, err
 			}
-			t, err := convertTerm(p, term)
+			t, err := convertTerm(p, term, b.mode)
 			if err != nil {
 				return nil, err
 			}
-			return p.ReduceTerm(f, t)
+			return p.ReduceTerm(f, t, b.mode)
 		},
 		"reduce": func(p *Polynomial, fe Expr) (Expr, error) {
-			f := &Polynomial{vars: p.vars, order: p.order}
-			if err := f.convert(fe); err != nil {
+			f := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+			if err := f.convert(fe, b.mode); err != nil {
 				return nil, err
 			}
-			return p.Reduce(f), nil
+			return p.Reduce(f, b.mode), nil
 		},
 		"reduceany": func(p *Polynomial, fns Expr) (Expr, error) {
 			var fn []*Polynomial
 			if v, ok := fns.(List); ok {
 				fn = make([]*Polynomial, len(v))
 				for i := 0; i < len(v); i++ {
-					f := &Polynomial{vars: p.vars, order: p.order}
-					if err := f.convert(v[i]); err != nil {
+					f := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+					if err := f.convert(v[i], b.mode); err != nil {
 						return nil, err
 					}
 					fn[i] = f
 				}
 			}
-			return p.ReduceAny(fn), nil
+			return p.ReduceAny(fn, b.mode), nil
 		},
 		"reducemany": func(p *Polynomial, fns Expr) (Expr, error) {
 			var fn []*Polynomial
 			if v, ok := fns.(List); ok {
 				fn = make([]*Polynomial, len(v))
 				for i := 0; i < len(v); i++ {
-					f := &Polynomial{vars: p.vars, order: p.order}
-					if err := f.convert(v[i]); err != nil {
+					f := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+					if err := f.convert(v[i], b.mode); err != nil {
 						return nil, err
 					}
 					fn[i] = f
 				}
 			}
-			h1 := p
-			for {
-				h2 := h1.ReduceAny(fn)
-				if h2.Equal(h1) {
-					return h1, nil
+			return p.normalForm(fn, func(f, h *Polynomial) {
+				fmt.Printf("reduced by %v to %v\n", f, h)
+			}), nil
+		},
+		"groebner": func(fns []*Polynomial, opts ...Expr) (Expr, error) {
+			name := "buchberger"
+			if len(opts) > 1 {
+				return nil, fmt.Errorf("too many options")
+			} else if len(opts) == 1 {
+				var err error
+				if name, err = convertName(opts[0]); err != nil {
+					return nil, err
+				}
+			}
+			alg, err := groebnerByName(name)
+			if err != nil {
+				return nil, err
+			}
+			basis, err := alg(fns)
+			if err != nil {
+				return nil, err
+			}
+			return polynomialList(basis), nil
+		},
+		"fglm": func(fns []*Polynomial, name Expr) (Expr, error) {
+			s, err := convertName(name)
+			if err != nil {
+				return nil, err
+			}
+			order, err := termOrderByName(s)
+			if err != nil {
+				return nil, err
+			}
+			basis, err := FGLM(fns, order)
+			if err != nil {
+				return nil, err
+			}
+			return polynomialList(basis), nil
+		},
+		"walk": func(fns []*Polynomial, from, to Expr) (Expr, error) {
+			if len(fns) == 0 {
+				return List{}, nil
+			}
+			n := len(fns[0].vars)
+			if n == 0 {
+				// all term orders agree on the constant polynomials
+				return polynomialList(fns), nil
+			}
+			src, err := convertOrder(from, n)
+			if err != nil {
+				return nil, err
+			}
+			dst, err := convertOrder(to, n)
+			if err != nil {
+				return nil, err
+			}
+			basis, err := Walk(fns, src, dst)
+			if err != nil {
+				return nil, err
+			}
+			if name, err := convertName(to); err == nil {
+				for i := range basis {
+					basis[i] = basis[i].withOrder(termOrders[name])
+				}
+			}
+			return polynomialList(basis), nil
+		},
+		"ideal": func(fns []*Polynomial) Expr {
+			return NewIdeal(fns)
+		},
+		"idealsum": func(a, b *Ideal) (Expr, error) {
+			return IdealSum(a, b)
+		},
+		"idealprod": func(a, b *Ideal) (Expr, error) {
+			return IdealProduct(a, b)
+		},
+		"intersect": func(a, b *Ideal) (Expr, error) {
+			return Intersect(a, b)
+		},
+		"quotient": func(a, b *Ideal) (Expr, error) {
+			return Quotient(a, b)
+		},
+		"saturate": func(a *Ideal, f *Polynomial) (Expr, error) {
+			return Saturate(a, f)
+		},
+		"contract": func(fns []*Polynomial) (Expr, error) {
+			return Contract(fns)
+		},
+		"inradical": func(f *Polynomial, I *Ideal) (Expr, error) {
+			ok, err := InRadical(f, I)
+			return Bool(ok), err
+		},
+		"radical": func(I *Ideal) (Expr, error) {
+			return Radical(I)
+		},
+		"primdec": func(I *Ideal) (Expr, error) {
+			primary, primes, err := PrimaryDecomposition(I)
+			if err != nil {
+				return nil, err
+			}
+			rval := make(List, len(primary))
+			for i := range primary {
+				rval[i] = List{primary[i], primes[i]}
+			}
+			return rval, nil
+		},
+		"minass": func(I *Ideal) (Expr, error) {
+			primes, err := MinimalPrimes(I)
+			if err != nil {
+				return nil, err
+			}
+			rval := make(List, len(primes))
+			for i := range primes {
+				rval[i] = primes[i]
+			}
+			return rval, nil
+		},
+		"prem": func(f, g *Polynomial, x Expr) (Expr, error) {
+			name, err := convertName(x)
+			if err != nil {
+				return nil, err
+			}
+			fns, err := convertPolynomials(List{f, g, Ident(name)}, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			i := sort.SearchStrings(fns[0].vars, name)
+			_, r, err := fns[0].PseudoDivide(fns[1], i)
+			if err != nil {
+				return nil, err
+			}
+			return r, nil
+		},
+		"gcd": func(fns []*Polynomial) (Expr, error) {
+			dense, err := univariate(fns)
+			if err != nil {
+				return nil, err
+			}
+			var g []Coeff
+			for _, a := range dense {
+				g = upolyGCD(fns[0].field, g, a)
+			}
+			return fns[0].fromDense(g), nil
+		},
+		"factor": func(p *Polynomial) (Expr, error) {
+			a, err := upolyFromPolynomial(p)
+			if err != nil {
+				return nil, err
+			}
+			lc, factors, mult, err := upolyFactor(p.field, a)
+			if err != nil {
+				return nil, err
+			}
+			var rval List
+			if !p.field.Equal(lc, p.field.One()) {
+				rval = append(rval, List{coeffExpr(p.field, lc), Num{big.NewRat(1, 1)}})
+			}
+			for i, q := range factors {
+				rval = append(rval, List{p.fromDense(q), Num{big.NewRat(int64(mult[i]), 1)}})
+			}
+			return rval, nil
+		},
+		"divmod": func(f, g *Polynomial) (Expr, error) {
+			fns, err := convertPolynomials(List{f, g}, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			dense, err := univariate(fns)
+			if err != nil {
+				return nil, err
+			}
+			if len(dense[1]) == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			q, r := upolyDivMod(fns[0].field, dense[0], dense[1])
+			return List{fns[0].fromDense(q), fns[0].fromDense(r)}, nil
+		},
+		"eval": func(f *Polynomial, points Expr) (Expr, error) {
+			list, ok := points.(List)
+			if !ok {
+				return nil, fmt.Errorf("invalid list of points")
+			}
+			dense, err := univariate([]*Polynomial{f})
+			if err != nil {
+				return nil, err
+			}
+			xs := make([]Coeff, len(list))
+			for i := range list {
+				if xs[i], err = convertCoeff(f, list[i], b.mode); err != nil {
+					return nil, err
+				}
+			}
+			values := upolyEvalMany(f.field, dense[0], xs)
+			rval := make(List, len(values))
+			for i, v := range values {
+				rval[i] = coeffExpr(f.field, v)
+			}
+			return rval, nil
+		},
+		"matrix": func(M *Matrix) Expr {
+			return M
+		},
+		"det": func(M *Matrix) (Expr, error) {
+			return M.Det()
+		},
+		"rank": func(M *Matrix) Expr {
+			return Num{big.NewRat(int64(M.Rank()), 1)}
+		},
+		"rref": func(M *Matrix) Expr {
+			return M.RREF()
+		},
+		"inverse": func(M *Matrix) (Expr, error) {
+			return M.Inverse()
+		},
+		"nullspace": func(M *Matrix) Expr {
+			basis := M.Nullspace()
+			rval := make(List, len(basis))
+			for i, v := range basis {
+				rval[i] = polynomialList(v)
+			}
+			return rval
+		},
+		"charpoly": func(M *Matrix, x Expr) (Expr, error) {
+			name, err := convertName(x)
+			if err != nil {
+				return nil, err
+			}
+			return M.Charpoly(name)
+		},
+		"nsolve": func(I *Ideal, digits Num) (Expr, error) {
+			if !digits.IsInt() || digits.Sign() <= 0 || digits.Num().BitLen() > 16 {
+				return nil, fmt.Errorf("invalid number of digits %v", digits)
+			}
+			sols, err := NSolve(I, int(digits.Num().Int64()))
+			if err != nil {
+				return nil, err
+			}
+			rval := make(List, len(sols))
+			for i, sol := range sols {
+				vals := make(List, len(sol))
+				for j, v := range sol {
+					vals[j] = Assign{Ident(I.vars[j]), v}
+				}
+				rval[i] = vals
+			}
+			return rval, nil
+		},
+		"triangularize": func(fns []*Polynomial) (Expr, error) {
+			systems, err := Triangularize(fns)
+			if err != nil {
+				return nil, err
+			}
+			rval := make(List, len(systems))
+			for i := range systems {
+				rval[i] = polynomialList(systems[i])
+			}
+			return rval, nil
+		},
+		"syzygies": func(fns []*Polynomial) (Expr, error) {
+			if b.mode == LaurentMode {
+				return nil, fmt.Errorf("syzygies are not supported in mode(%v)", b.mode)
+			}
+			syz, err := Syzygies(fns)
+			if err != nil {
+				return nil, err
+			}
+			rval := make(List, len(syz))
+			for i := range syz {
+				rval[i] = polynomialList(syz[i])
+			}
+			return rval, nil
+		},
+		"modulegb": func(vectors Expr, opts ...Expr) (Expr, error) {
+			pot := true
+			if len(opts) > 1 {
+				return nil, fmt.Errorf("too many options")
+			} else if len(opts) == 1 {
+				name, err := convertName(opts[0])
+				if err != nil {
+					return nil, err
+				}
+				switch name {
+				case "pot":
+				case "top":
+					pot = false
+				default:
+					return nil, fmt.Errorf("unknown module order %q", name)
+				}
+			}
+			M, vecs, err := convertVectors(vectors, pot, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			basis := M.GroebnerModule(vecs)
+			rval := make(List, len(basis))
+			for i := range basis {
+				rval[i] = polynomialList(M.components(basis[i]))
+			}
+			return rval, nil
+		},
+		"resolution": func(I *Ideal) (Expr, error) {
+			res, err := NewResolution(I)
+			if err != nil {
+				return nil, err
+			}
+			return res.Maps(), nil
+		},
+		"betti": func(I *Ideal) (Expr, error) {
+			res, err := NewResolution(I)
+			if err != nil {
+				return nil, err
+			}
+			return res.Betti(), nil
+		},
+		"homogenize": func(p, h Expr, weights ...Expr) (Expr, error) {
+			name, err := convertName(h)
+			if err != nil {
+				return nil, err
+			}
+			if I, ok := p.(*Ideal); ok {
+				w, err := convertWeights(weights, len(I.vars))
+				if err != nil {
+					return nil, err
+				}
+				return I.Homogenize(name, w)
+			}
+			fns, list, err := convertGraded(p, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			w, err := convertWeights(weights, len(fns[0].vars))
+			if err != nil {
+				return nil, err
+			}
+			for i := range fns {
+				if fns[i], err = fns[i].Homogenize(name, w); err != nil {
+					return nil, err
 				}
-				h1 = h2
 			}
+			if list {
+				return polynomialList(fns), nil
+			}
+			return fns[0], nil
+		},
+		"dehomogenize": func(p, h Expr) (Expr, error) {
+			name, err := convertName(h)
+			if err != nil {
+				return nil, err
+			}
+			if I, ok := p.(*Ideal); ok {
+				return I.Dehomogenize(name), nil
+			}
+			fns, list, err := convertGraded(p, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			for i := range fns {
+				fns[i] = fns[i].Dehomogenize(name)
+			}
+			if list {
+				return polynomialList(fns), nil
+			}
+			return fns[0], nil
+		},
+		"ishomogeneous": func(p Expr, weights ...Expr) (Expr, error) {
+			if I, ok := p.(*Ideal); ok {
+				w, err := convertWeights(weights, len(I.vars))
+				if err != nil {
+					return nil, err
+				}
+				return Bool(I.IsHomogeneous(w)), nil
+			}
+			fns, _, err := convertGraded(p, b.field, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			w, err := convertWeights(weights, len(fns[0].vars))
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range fns {
+				if _, ok := isHomogeneous(f, w); !ok {
+					return Bool(false), nil
+				}
+			}
+			return Bool(true), nil
+		},
+		"hilbert": func(I *Ideal) (Expr, error) {
+			return NewHilbertSeries(I)
+		},
+		"hilbertpoly": func(I *Ideal) (Expr, error) {
+			hs, err := NewHilbertSeries(I)
+			if err != nil {
+				return nil, err
+			}
+			return hs.Polynomial(), nil
+		},
+		"dim": func(I *Ideal) (Expr, error) {
+			hs, err := NewHilbertSeries(I)
+			if err != nil {
+				return nil, err
+			}
+			return Num{big.NewRat(int64(hs.Dim()), 1)}, nil
+		},
+		"degree": func(I *Ideal) (Expr, error) {
+			hs, err := NewHilbertSeries(I)
+			if err != nil {
+				return nil, err
+			}
+			return Num{hs.Degree()}, nil
+		},
+		"standardmonomials": func(I *Ideal) (Expr, error) {
+			q, err := newQuotientRing(I)
+			if err != nil {
+				return nil, err
+			}
+			return polynomialList(q.StandardMonomials()), nil
+		},
+		"multmatrix": func(I *Ideal, f *Polynomial) (Expr, error) {
+			I, F, err := commonRing(I, NewIdeal([]*Polynomial{f}))
+			if err != nil {
+				return nil, err
+			}
+			q, err := newQuotientRing(I)
+			if err != nil {
+				return nil, err
+			}
+			if len(F.gens) == 0 {
+				f = &Polynomial{vars: I.vars, order: I.order, field: I.field}
+			} else {
+				f = F.gens[0]
+			}
+			m := q.MultMatrix(f)
+			rows := make(List, len(m))
+			for i := range m {
+				row := make(List, len(m[i]))
+				for j := range m[i] {
+					row[j] = coeffExpr(I.field, m[i][j])
+				}
+				rows[i] = row
+			}
+			return rows, nil
+		},
+		"gbstats": func(fns []*Polynomial) Expr {
+			return CompareGroebner(fns)
+		},
+		"gf": func(p, k Num, modulus ...Expr) (Expr, error) {
+			if !p.IsInt() || !k.IsInt() || p.Sign() <= 0 || k.Sign() <= 0 ||
+				k.Num().BitLen() > 16 || p.Num().BitLen() > 64 {
+				return nil, fmt.Errorf("invalid field size %v^%v", p, k)
+			}
+			if len(modulus) > 1 {
+				return nil, fmt.Errorf("expected at most one modulus")
+			}
+			if len(modulus) == 1 {
+				m, err := NewPolynomial(modulus[0], Rationals, b.mode)
+				if err != nil {
+					return nil, err
+				}
+				f, err := NewGaloisFieldModulus(p.Num().Uint64(), m)
+				if err != nil {
+					return nil, err
+				}
+				if int64(f.Degree()) != k.Num().Int64() {
+					return nil, fmt.Errorf("modulus %v does not have degree %v", m, k)
+				}
+				return f, nil
+			}
+			f, err := NewGaloisField(p.Num().Uint64(), int(k.Num().Int64()))
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		},
+		"generator": func(f Field) (Expr, error) {
+			ext, ok := f.(*ExtensionField)
+			if !ok {
+				return nil, fmt.Errorf("field %v has no generator", f)
+			}
+			return Elem{ext, ext.Generator()}, nil
+		},
+		// field(a, m) adjoins a root a of m to the rationals and binds a to
+		// it. field(F) selects the coefficient field of the polynomials,
+		// e.g. field(a) or field(i), and field(QQ) switches back to the
+		// rationals.
+		"field": func(name Expr, modulus ...Expr) (Expr, error) {
+			if len(modulus) > 1 {
+				return nil, fmt.Errorf("expected at most one modulus")
+			}
+			if len(modulus) == 0 {
+				f, err := convertField(name)
+				if err != nil {
+					return nil, err
+				}
+				b.field = f
+				return f, nil
+			}
+			ident, ok := name.(Ident)
+			if !ok {
+				return nil, fmt.Errorf("invalid generator %v, expected a name", name)
+			}
+			m, err := NewPolynomial(modulus[0], Rationals, b.mode)
+			if err != nil {
+				return nil, err
+			}
+			if len(m.vars) != 1 || m.vars[0] != string(ident) {
+				return nil, fmt.Errorf("modulus %v is not a polynomial in %v", m, ident)
+			}
+			f, err := NewNumberField(m)
+			if err != nil {
+				return nil, err
+			}
+			b.globals[string(ident)] = Elem{f, f.Generator()}
+			return f, nil
+		},
+		"over": func(p *Polynomial, field Expr) (Expr, error) {
+			f, err := convertField(field)
+			if err != nil {
+				return nil, err
+			}
+			if p.field != Rationals {
+				return nil, fmt.Errorf("invalid polynomial over %v", p.field)
+			}
+			return p.mapCoeffs(f, func(c Coeff) (Coeff, error) {
+				return f.FromRat(c.(*big.Rat))
+			})
+		},
+		"issymmetric": func(p *Polynomial) Expr {
+			return Bool(IsSymmetric(p))
+		},
+		"tosymmetric": func(p *Polynomial) (Expr, error) {
+			return ToSymmetric(p)
+		},
+		"reynolds": func(p *Polynomial, group Expr) (Expr, error) {
+			list, ok := group.(List)
+			if !ok {
+				return nil, fmt.Errorf("invalid group %v, expected a list of permutations", group)
+			}
+			gens := make([][]string, len(list))
+			for i := range list {
+				perm, err := convertVars(list[i])
+				if err != nil {
+					return nil, fmt.Errorf("invalid permutation %v", list[i])
+				}
+				gens[i] = perm
+			}
+			return Reynolds(p, gens)
 		},
 	}
 }
@@ -192,21 +760,32 @@ func (b *Bruno) executeCall(call Call) (Expr, error) {
 	v := reflect.ValueOf(fn)
 	fnT := v.Type()
 
-	if fnT.NumIn() != len(call.Args) {
+	numIn := fnT.NumIn()
+	if fnT.IsVariadic() {
+		if len(call.Args) < numIn-1 {
+			return nil, fmt.Errorf("invalid number of args. expected at least %d, got %d.\n",
+				numIn-1, len(call.Args))
+		}
+	} else if numIn != len(call.Args) {
 		return nil, fmt.Errorf("invalid number of args. expected %d, got %d.\n",
-			fnT.NumIn(), len(call.Args))
+			numIn, len(call.Args))
 	}
 
 	args := make([]reflect.Value, len(call.Args))
 	for i := 0; i < len(args); i++ {
 		gotV := reflect.ValueOf(call.Args[i])
 		gotT := gotV.Type()
-		wantT := fnT.In(i)
+		var wantT reflect.Type
+		if fnT.IsVariadic() && i >= numIn-1 {
+			wantT = fnT.In(numIn - 1).Elem()
+		} else {
+			wantT = fnT.In(i)
+		}
 		switch {
 		case gotT.AssignableTo(wantT):
 			args[i] = gotV
 		case wantT == reflect.TypeOf(&Polynomial{}):
-			p, err := NewPolynomial(call.Args[i])
+			p, err := NewPolynomial(call.Args[i], b.field, b.mode)
 			if err != nil {
 				return nil, fmt.Errorf("invalid parameter %d: %v", i+1, err)
 			}
@@ -217,6 +796,34 @@ func (b *Bruno) executeCall(call Call) (Expr, error) {
 				return nil, fmt.Errorf("invalid parameter %d: %v", i+1, err)
 			}
 			args[i] = reflect.ValueOf(vars)
+		case wantT == reflect.TypeOf([]*Polynomial{}):
+			fns, err := convertPolynomials(call.Args[i], b.field, b.mode)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %d: %v", i+1, err)
+			}
+			if b.mode == LaurentMode {
+				if fns, err = contractGenerators(fns); err != nil {
+					return nil, err
+				}
+			}
+			args[i] = reflect.ValueOf(fns)
+		case wantT == reflect.TypeOf(&Ideal{}):
+			fns, err := convertPolynomials(call.Args[i], b.field, b.mode)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %d: %v", i+1, err)
+			}
+			if b.mode == LaurentMode {
+				if fns, err = contractGenerators(fns); err != nil {
+					return nil, err
+				}
+			}
+			args[i] = reflect.ValueOf(NewIdeal(fns))
+		case wantT == reflect.TypeOf(&Matrix{}):
+			M, err := convertMatrix(call.Args[i], b.field, b.mode)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %d: %v", i+1, err)
+			}
+			args[i] = reflect.ValueOf(M)
 		default:
 			return nil, fmt.Errorf("invalid parameter %d.", i+1)
 		}
@@ -281,6 +888,9 @@ func (b *Bruno) ExecExpr(expr Expr) (Expr, error) {
 		if ok1 && ok2 {
 			return Num{new(big.Rat).Add(an.Rat, bn.Rat)}, nil
 		}
+		if v, err := elemArith('+', a, b); v != nil || err != nil {
+			return v, err
+		}
 		return Add{a, b}, nil
 	case Sub:
 		a, err := b.ExecExpr(x.A)
@@ -296,6 +906,9 @@ func (b *Bruno) ExecExpr(expr Expr) (Expr, error) {
 		if ok1 && ok2 {
 			return Num{new(big.Rat).Sub(an.Rat, bn.Rat)}, nil
 		}
+		if v, err := elemArith('-', a, b); v != nil || err != nil {
+			return v, err
+		}
 		return Sub{a, b}, nil
 	case Mul:
 		a, err := b.ExecExpr(x.A)
@@ -311,6 +924,9 @@ func (b *Bruno) ExecExpr(expr Expr) (Expr, error) {
 		if ok1 && ok2 {
 			return Num{new(big.Rat).Mul(an.Rat, bn.Rat)}, nil
 		}
+		if v, err := elemArith('*', a, b); v != nil || err != nil {
+			return v, err
+		}
 		return Mul{a, b}, nil
 	case Div:
 		a, err := b.ExecExpr(x.A)
@@ -324,8 +940,14 @@ func (b *Bruno) ExecExpr(expr Expr) (Expr, error) {
 		an, ok1 := a.(Num)
 		bn, ok2 := b.(Num)
 		if ok1 && ok2 {
+			if bn.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
 			return Num{new(big.Rat).Quo(an.Rat, bn.Rat)}, nil
 		}
+		if v, err := elemArith('/', a, b); v != nil || err != nil {
+			return v, err
+		}
 		return Div{a, b}, nil
 	case Pow:
 		a, err := b.ExecExpr(x.A)
@@ -336,6 +958,9 @@ func (b *Bruno) ExecExpr(expr Expr) (Expr, error) {
 		if err != nil {
 			return nil, err
 		}
+		if v, err := elemArith('^', a, b); v != nil || err != nil {
+			return v, err
+		}
 		return Pow{a, b}, nil
 	case List:
 		result := make(List, len(x))
@@ -359,17 +984,32 @@ func (b *Bruno) Exec(input string) (Expr, error) {
 	return b.ExecExpr(expr)
 }
 
-func convertTerm(p *Polynomial, expr Expr) (Term, error) {
-	q := &Polynomial{vars: p.vars, order: p.order}
-	if err := q.convert(expr); err != nil {
-		return nil, err
+func convertTerm(p *Polynomial, expr Expr, mode ExponentMode) (Term, error) {
+	q := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	if err := q.convert(expr, mode); err != nil {
+		return Term{}, err
 	}
 	if len(q.items) != 1 {
-		return nil, fmt.Errorf("invalid term")
+		return Term{}, fmt.Errorf("invalid term")
 	}
 	return q.items[0].T, nil
 }
 
+// convertCoeff converts a constant into a coefficient of the field of p.
+func convertCoeff(p *Polynomial, expr Expr, mode ExponentMode) (Coeff, error) {
+	q := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	if err := q.convert(expr, mode); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(q.items) == 0:
+		return p.field.Zero(), nil
+	case len(q.items) == 1 && termEqual(q.items[0].T, NewTerm(len(p.vars))):
+		return q.items[0].C, nil
+	}
+	return nil, fmt.Errorf("invalid constant %v", expr)
+}
+
 func convertVars(expr Expr) ([]string, error) {
 	var list []string
 	if v, ok := expr.(List); ok {
@@ -387,6 +1027,249 @@ func convertVars(expr Expr) ([]string, error) {
 	return list, nil
 }
 
+// convertPolynomials converts a list of expressions into polynomials which
+// share the same variables. The term order and the field are taken from the
+// first polynomial in the list. Ideals are converted into their generators.
+func convertPolynomials(expr Expr, def Field, mode ExponentMode) ([]*Polynomial, error) {
+	if I, ok := expr.(*Ideal); ok {
+		return I.gens, nil
+	}
+	list, ok := expr.(List)
+	if !ok {
+		return nil, fmt.Errorf("invalid polynomial list")
+	}
+	var (
+		vars  []string
+		order TermOrder = LexTermOrder
+		field           = Rationals
+		first           = true
+		err   error
+	)
+	seen := make(map[Ident]struct{})
+	for i := range list {
+		if p, ok := list[i].(*Polynomial); ok {
+			for _, v := range p.vars {
+				seen[Ident(v)] = struct{}{}
+			}
+			if first {
+				order, field, first = p.order, p.field, false
+			}
+		} else {
+			collectVars2(list[i], seen)
+		}
+	}
+	if first {
+		if field, err = collectField(list, def); err != nil {
+			return nil, err
+		}
+	}
+	for v := range seen {
+		vars = append(vars, string(v))
+	}
+	sort.Strings(vars)
+	fns := make([]*Polynomial, len(list))
+	for i := range list {
+		if p, ok := list[i].(*Polynomial); ok {
+			if p.field != field {
+				return nil, fmt.Errorf("incompatible fields %v and %v", p.field, field)
+			}
+			q, err := p.embed(vars, order)
+			if err != nil {
+				return nil, err
+			}
+			fns[i] = q
+			continue
+		}
+		p := &Polynomial{vars: vars, order: order, field: field}
+		if err := p.convert(list[i], mode); err != nil {
+			return nil, err
+		}
+		fns[i] = p
+	}
+	return fns, nil
+}
+
+// convertGraded converts a polynomial or a list of polynomials for the
+// homogenization builtins. The second return value reports whether expr was
+// a list.
+func convertGraded(expr Expr, def Field, mode ExponentMode) ([]*Polynomial, bool, error) {
+	if _, ok := expr.(List); ok {
+		fns, err := convertPolynomials(expr, def, mode)
+		if err == nil && len(fns) == 0 {
+			err = fmt.Errorf("empty polynomial list")
+		}
+		return fns, true, err
+	}
+	p, err := NewPolynomial(expr, def, mode)
+	if err != nil {
+		return nil, false, err
+	}
+	return []*Polynomial{p}, false, nil
+}
+
+// convertWeights converts the optional weight vector of the variables in
+// alphabetical order. Without a weight vector all weights are 1.
+func convertWeights(opts []Expr, n int) ([]int64, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	list, ok := opts[0].(List)
+	if len(opts) > 1 || !ok || len(list) != n {
+		return nil, fmt.Errorf("expected %d weights", n)
+	}
+	w := make([]int64, n)
+	for i := range list {
+		x, ok := list[i].(Num)
+		if !ok || !x.IsInt() || x.Sign() <= 0 || x.Num().BitLen() > 31 {
+			return nil, fmt.Errorf("weights must be positive integers")
+		}
+		w[i] = x.Num().Int64()
+	}
+	return w, nil
+}
+
+// convertVectors converts a list of vectors, given as lists of polynomials
+// of equal length, into elements of a free module.
+func convertVectors(expr Expr, pot bool, def Field, mode ExponentMode) (*freeModule, []*Polynomial, error) {
+	list, ok := expr.(List)
+	if !ok || len(list) == 0 {
+		return nil, nil, fmt.Errorf("invalid vector list")
+	}
+	var flat List
+	rank := -1
+	for _, v := range list {
+		comps, ok := v.(List)
+		if !ok || len(comps) == 0 || (rank >= 0 && len(comps) != rank) {
+			return nil, nil, fmt.Errorf("invalid vector %v", v)
+		}
+		rank = len(comps)
+		flat = append(flat, comps...)
+	}
+	fns, err := convertPolynomials(flat, def, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	M := newFreeModule(fns[0].vars, fns[0].order, fns[0].field, rank, pot)
+	vecs := make([]*Polynomial, len(list))
+	for i := range vecs {
+		if vecs[i], err = M.vector(fns[i*rank : (i+1)*rank]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return M, vecs, nil
+}
+
+// convertMatrix converts a list of rows into a matrix, whose entries are
+// polynomials with the same variables.
+func convertMatrix(expr Expr, def Field, mode ExponentMode) (*Matrix, error) {
+	if M, ok := expr.(*Matrix); ok {
+		return M, nil
+	}
+	rows, ok := expr.(List)
+	if !ok || len(rows) == 0 {
+		return nil, fmt.Errorf("invalid matrix")
+	}
+	var entries List
+	cols := -1
+	for _, r := range rows {
+		row, ok := r.(List)
+		if !ok || (cols >= 0 && len(row) != cols) {
+			return nil, fmt.Errorf("invalid matrix, the rows must be lists of the same length")
+		}
+		cols = len(row)
+		entries = append(entries, row...)
+	}
+	fns, err := convertPolynomials(entries, def, mode)
+	if err != nil {
+		return nil, err
+	}
+	M := &Matrix{vars: collectVars(entries), order: LexTermOrder, field: def, cols: cols}
+	if len(fns) > 0 {
+		M.vars, M.order, M.field = fns[0].vars, fns[0].order, fns[0].field
+	}
+	for i := range rows {
+		M.rows = append(M.rows, fns[i*cols:(i+1)*cols])
+	}
+	return M, nil
+}
+
+func polynomialList(fns []*Polynomial) List {
+	rval := make(List, len(fns))
+	for i := range fns {
+		rval[i] = fns[i]
+	}
+	return rval
+}
+
+// convertName converts an identifier or string into a name, e.g. for
+// selecting an algorithm.
+func convertName(expr Expr) (string, error) {
+	switch x := expr.(type) {
+	case Ident:
+		return string(x), nil
+	case Str:
+		return string(x), nil
+	}
+	return "", fmt.Errorf("invalid name %v", expr)
+}
+
+// convertField converts a field, an element of a field or the name QQ of
+// the rationals into a field.
+func convertField(expr Expr) (Field, error) {
+	switch x := expr.(type) {
+	case Field:
+		return x, nil
+	case Elem:
+		return x.F, nil
+	case Ident:
+		if x == "QQ" {
+			return Rationals, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid field %v", expr)
+}
+
+// convertOrder converts the name of a term order, a weight vector or an
+// order matrix into a matrix order on terms in n variables. The columns
+// correspond to the variables in alphabetical order. Ties of a weight
+// vector are broken by the lexicographical order.
+func convertOrder(expr Expr, n int) (matrixOrder, error) {
+	if name, err := convertName(expr); err == nil {
+		return namedOrderMatrix(name, n)
+	}
+	list, ok := expr.(List)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("invalid term order %v", expr)
+	}
+	if _, ok := list[0].(Num); ok {
+		list = List{list}
+		lex, _ := namedOrderMatrix("lex", n)
+		for _, row := range lex {
+			lst := make(List, n)
+			for j := range row {
+				lst[j] = Num{row[j]}
+			}
+			list = append(list, lst)
+		}
+	}
+	rows := make([][]*big.Rat, len(list))
+	for i := range list {
+		row, ok := list[i].(List)
+		if !ok {
+			return nil, fmt.Errorf("invalid term order %v", expr)
+		}
+		rows[i] = make([]*big.Rat, len(row))
+		for j := range row {
+			x, ok := row[j].(Num)
+			if !ok {
+				return nil, fmt.Errorf("invalid term order %v", expr)
+			}
+			rows[i][j] = x.Rat
+		}
+	}
+	return newMatrixOrder(rows, n)
+}
+
 func main() {
 	fmt.Println("Bruno 0.1 (2014-03-22) -- \"Übungszettel 1\"")
 	fmt.Println("Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>")
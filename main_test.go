@@ -4,6 +4,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -139,17 +140,613 @@ var brunoTests = []struct {
 		"reducemany(f, [x + -1*y^2*z2, y + -1*z*z2, z + -1*z2^3, z2^3 + -1*z2])",
 		"0",
 	},
+	{
+		"groebner([x^2 + y, x*y + -1])",
+		"[1*x + 1*y^2 1*y^3 + 1]",
+	},
+	{
+		"groebner([totalorder(p(x^3 + -2*x*y)), x^2*y + -2*y^2 + x])",
+		"[1*x^2 1*x*y 1*y^2 + -1/2*x]",
+	},
+	{
+		"groebner([totalorder(p(x^3 + -2*x*y)), x^2*y + -2*y^2 + x], \"modular\")",
+		"[1*x^2 1*x*y 1*y^2 + -1/2*x]",
+	},
+	{
+		"groebner([3*x^2 + 1/7*y, 2/3*x*y + -11], \"modular\")",
+		"[1*x + 2/693*y^2 1*y^3 + 22869/4]",
+	},
+	{
+		"gf(2, 8)",
+		"GF(2^8)",
+	},
+	{
+		"a = generator(gf(2, 8))",
+		"a = a",
+	},
+	{
+		"a^8",
+		"(a^4 + a^3 + a^2 + 1)",
+	},
+	{
+		"a^255",
+		"1",
+	},
+	{
+		"(a^3 + a + 1) * (a^7 + a^4 + a^3)",
+		"1",
+	},
+	{
+		"generator(gf(7, 1))",
+		"5",
+	},
+	{
+		"lc(p(a^2*x^2 + a*x + 1))",
+		"a^2",
+	},
+	{
+		"groebner([over(x^2 + y, gf(2, 1)), x*y + 1])",
+		"[1*x + 1*y^2 1*y^3 + 1]",
+	},
+	{
+		"p(x^2 - 2*x + x - y)",
+		"1*x^2 + -1*x + -1*y",
+	},
+	{
+		"field(r, r^2 - 2)",
+		"QQ[r]/(r^2 + -2)",
+	},
+	{
+		"1 / (r + 1)",
+		"(r + -1)",
+	},
+	{
+		"groebner([x^2 - 2, x*y - r])",
+		"[1*x + -1*r*y 1*y^2 + -1]",
+	},
+	{
+		"field(s, s^2 + 2*s + 1)",
+		"error: modulus 1*s^2 + 2*s + 1 is not square free",
+	},
+	{
+		"p(x^3000000000 * x^3000000000 + x^2)",
+		"1*x^6000000000 + 1*x^2",
+	},
+	{
+		"reduce(p(x^3000000000*y + 1), x^2999999999)",
+		"1",
+	},
+	{
+		"totalorder(p(x^(1/2)*y^3 + x^4 + y^(7/2)))",
+		"error: non-integer exponent in x^1/2, use mode(puiseux) for Puiseux polynomials",
+	},
+	{
+		"mode(puiseux)",
+		"puiseux",
+	},
+	{
+		"totalorder(p(x^(1/2)*y^3 + x^4 + y^(7/2)))",
+		"1*x^4 + 1*x^1/2*y^3 + 1*y^7/2",
+	},
+	{
+		"p((x^(1/2) + 1)*(x^(1/2) - 1) + y^0.5*y^(-3/2))",
+		"1*x + -1 + 1*y^-1",
+	},
+	{
+		"mode(polynomial)",
+		"polynomial",
+	},
+	{
+		"groebner([totalorder(p(x^3 + -2*x*y)), x^2*y + -2*y^2 + x], \"f4\")",
+		"[1*x^2 1*x*y 1*y^2 + -1/2*x]",
+	},
+	{
+		"groebner([w+x+y+z, w*x+x*y+y*z+z*w, w*x*y+x*y*z+y*z*w+z*w*x, w*x*y*z-1], \"f4\")",
+		"[1*w + 1*x + 1*y + 1*z 1*x^2 + 2*x*z + 1*z^2 1*x*y + -1*x*z + 1*y^2*z^4 + 1*y*z + -2*z^2 1*x*z^4 + -1*x + 1*z^5 + -1*z 1*y^3*z^2 + 1*y^2*z^3 + -1*y + -1*z 1*y^2*z^6 + -1*y^2*z^2 + -1*z^4 + 1]",
+	},
+	{
+		"groebner([w+x+y+z, w*x+x*y+y*z+z*w, w*x*y+x*y*z+y*z*w+z*w*x, w*x*y*z-1], \"gvw\")",
+		"[1*w + 1*x + 1*y + 1*z 1*x^2 + 2*x*z + 1*z^2 1*x*y + -1*x*z + 1*y^2*z^4 + 1*y*z + -2*z^2 1*x*z^4 + -1*x + 1*z^5 + -1*z 1*y^3*z^2 + 1*y^2*z^3 + -1*y + -1*z 1*y^2*z^6 + -1*y^2*z^2 + -1*z^4 + 1]",
+	},
+	{
+		"groebner([x*y*z - 1, x + y - z^2, x^3 - y], \"gvw\")",
+		"[1*x + -2/3*z^10 + -1/3*z^9 + -2/3*z^8 + -1/3*z^7 + 1/3*z^6 + 7/3*z^5 + 5/3*z^4 + 7/3*z^3 + 2/3*z^2 + -1/3 1*y + 2/3*z^10 + 1/3*z^9 + 2/3*z^8 + 1/3*z^7 + -1/3*z^6 + -7/3*z^5 + -5/3*z^4 + -7/3*z^3 + -5/3*z^2 + 1/3 1*z^11 + -4*z^6 + -1*z^2 + 2*z + -1]",
+	},
+	{
+		"gbstats([totalorder(p(x^3 + -2*x*y)), x^2*y + -2*y^2 + x])",
+		"algorithm       pairs  skipped reductions     zero\n" +
+			"buchberger         10        5          5        2\n" +
+			"gvw                11        6          5        0",
+	},
+	{
+		"grevlexorder(p(x^2*z + x*y^2 + y^3 + x^3 + x*y*z))",
+		"1*x^3 + 1*x*y^2 + 1*y^3 + 1*x^2*z + 1*x*y*z",
+	},
+	{
+		"fglm(groebner([grevlexorder(p(x^2 + y^2 + z^2 - 1)), x*y*z - 1, x + y - z^2]), lex)",
+		"[1*x + 1*y + -1*z^2 1*y^2 + -1*y*z^2 + 1/2*z^4 + 1/2*z^2 + -1/2 1*z^5 + 1*z^3 + -1*z + -2]",
+	},
+	{
+		"fglm(groebner([x^2 + y]), lex)",
+		"error: fglm requires a zero-dimensional ideal",
+	},
+	{
+		"walk(groebner([grevlexorder(p(x^2*y - z^3)), x*z - y^2]), grevlex, lex)",
+		"[1*x^2*y + -1*z^3 1*x*y^3 + -1*z^4 1*x*z + -1*y^2 1*y^5 + -1*z^5]",
+	},
+	{
+		"walk(groebner([x^3 - y*z, y^2 - x*z]), lex, [1, 2, 3])",
+		"[1*y^3 + -1*x^4 1*y*z + -1*x^3 1*x*z + -1*y^2]",
+	},
+	{
+		"walk(groebner([x^3 - y*z, y^2 - x*z]), lex, [[1, -1, 0], [0, 1, 0], [0, 0, 1]])",
+		"error: order matrix is not a well-ordering",
+	},
+	{
+		"idealsum(ideal([x^2*y, x*y^2]), [x^3, y])",
+		"<1*x^2*y, 1*x*y^2, 1*x^3, 1*y>",
+	},
+	{
+		"idealprod([x^2*y, x*y^2], [x^3, y])",
+		"<1*x^5*y, 1*x^2*y^2, 1*x^4*y^2, 1*x*y^3>",
+	},
+	{
+		"intersect([x^2 - 1], [x - 1])",
+		"<1*x^2 + -1>",
+	},
+	{
+		"intersect([x], [y])",
+		"<1*x*y>",
+	},
+	{
+		"quotient([x^2, x*y], [x, y])",
+		"<1*x>",
+	},
+	{
+		"saturate([x^2 - x, x*y], x)",
+		"<1*x + -1, 1*y>",
+	},
+	{
+		"inradical(x + y, [x^2, y^3])",
+		"true",
+	},
+	{
+		"inradical(x, [x^2 + y])",
+		"false",
+	},
+	{
+		"radical([x^4 - 4*x^2 + 4, y^2 - 2*x*y + x^2])",
+		"<1*x + -1*y, 1*y^2 + -2>",
+	},
+	{
+		"radical([x^2 + y])",
+		"error: radical requires a zero-dimensional ideal",
+	},
+	{
+		"radical([x^4 + a*x^2 + a^2, y^2])",
+		"<1*x^2 + (a^7 + a^2 + 1)*x + a, 1*y>",
+	},
+	{
+		"hilbert([x^2, y^3])",
+		"(t^3 + 2*t^2 + 2*t + 1)",
+	},
+	{
+		"hilbert([x*z, y*z])",
+		"(-1*t^2 + t + 1)/(1 - t)^2",
+	},
+	{
+		"dim([x*z, y*z])",
+		"2",
+	},
+	{
+		"degree([y - x^2, z - x^3])",
+		"3",
+	},
+	{
+		"hilbertpoly([x^2 - y*z])",
+		"2*s + 1",
+	},
+	{
+		"dim([x - 1, x])",
+		"-1",
+	},
+	{
+		"standardmonomials([x^2 - 2, y^2 - 3])",
+		"[1 1*y 1*x 1*x*y]",
+	},
+	{
+		"multmatrix([x^2 - 2, y^2 - 3], x*y + 1)",
+		"[[1 0 0 6] [0 1 2 0] [0 3 1 0] [1 0 0 1]]",
+	},
+	{
+		"standardmonomials([x^2 + y])",
+		"error: quotient ring requires a zero-dimensional ideal",
+	},
+	{
+		"primdec([x^2 - 2, y^2 - 2])",
+		"[[<1*x + -1*y, 1*y^2 + -2> <1*x + -1*y, 1*y^2 + -2>] [<1*x + 1*y, 1*y^2 + -2> <1*x + 1*y, 1*y^2 + -2>]]",
+	},
+	{
+		"primdec([x^3 - x^2])",
+		"[[<1*x + -1> <1*x + -1>] [<1*x^2> <1*x>]]",
+	},
+	{
+		"minass([x^8 - 1, y^2])",
+		"[<1*x + 1, 1*y> <1*x + -1, 1*y> <1*x^2 + 1, 1*y> <1*x^4 + 1, 1*y>]",
+	},
+	{
+		"primdec([x^2 + y])",
+		"[[<1*x^2 + 1*y> <1*x^2 + 1*y>]]",
+	},
+	{
+		"minass([x*y])",
+		"[<1*y> <1*x>]",
+	},
+	{
+		"primdec([x^2, x*y])",
+		"[[<1*x> <1*x>] [<1*x^2, 1*y> <1*x, 1*y>]]",
+	},
+	{
+		"minass([x*y - z^2, x*z - y])",
+		"[<1*y, 1*z> <1*x^2 + -1*z, 1*x*y + -1*z^2, 1*x*z + -1*y, 1*y^2 + -1*z^3>]",
+	},
+	{
+		"prem(x^3 + y*x + 1, y*x^2 - 1, x)",
+		"1*x*y^3 + 1*x*y + 1*y^2",
+	},
+	{
+		"triangularize([x^2 + y^2 - 1, x - y])",
+		"[[1*y^2 + -1/2 1*x + -1*y]]",
+	},
+	{
+		"triangularize([x*y, x*z])",
+		"[[1*x*y] [1*y 1*x*z] [1*z 1*y]]",
+	},
+	{
+		"triangularize([x*y - 1, x])",
+		"[]",
+	},
+	{
+		"syzygies([x^2 - y, x*y, y^2])",
+		"[[1*y -1*x 1] [0 1*y -1*x]]",
+	},
+	{
+		"modulegb([[x, y], [y, x]])",
+		"[[1*x 1*y] [1*y 1*x] [0 1*x^2 + -1*y^2]]",
+	},
+	{
+		"modulegb([[x, y], [y, x]], top)",
+		"[[1*x 1*y] [1*y 1*x]]",
+	},
+	{
+		"resolution([x^2, x*y, y^2])",
+		"[[[1*x^2 1*x*y 1*y^2]] [[1*y 0] [-1*x 1*y] [0 -1*x]]]",
+	},
+	{
+		"betti([x*z - y^2, y*w - z^2, x*w - y*z])",
+		"       0 1 2\ntotal: 1 3 2\n    0: 1 . .\n    1: . 3 2",
+	},
+	{
+		"betti([x^2, y^2, z^2, x*y*z])",
+		"       0 1 2 3\ntotal: 1 4 6 3\n    0: 1 . . .\n    1: . 3 . .\n    2: . 1 6 3",
+	},
+	{
+		"betti([x^2 + y])",
+		"error: resolution requires a homogeneous ideal",
+	},
+	{
+		"homogenize(x^3 + x*y + 1, h)",
+		"1*h^3 + 1*h*x*y + 1*x^3",
+	},
+	{
+		"homogenize(ideal([y - x^2, z - x^3]), w)",
+		"<-1*w*y + 1*x^2, -1*w*z + 1*x*y, -1*x*z + 1*y^2>",
+	},
+	{
+		"dehomogenize([w*x - y^2, w^2 - x], w)",
+		"[1*x + -1*y^2 -1*x + 1]",
+	},
+	{
+		"ishomogeneous(x^2 + y)",
+		"false",
+	},
+	{
+		"ishomogeneous(x^2 + y, [1, 2])",
+		"true",
+	},
+	{
+		"ishomogeneous(ideal([x^2 - y^2 + x, x]))",
+		"true",
+	},
+	{
+		"p(x*y^-1)",
+		"error: negative exponent in y^-1, use mode(laurent) for Laurent polynomials",
+	},
+	{
+		"p((x^2 + x*y)/(2*x))",
+		"1/2*x + 1/2*y",
+	},
+	{
+		"mode(laurent)",
+		"laurent",
+	},
+	{
+		"p((x^2 + x*y)/(2*x*y^2))",
+		"1/2*x*y^-2 + 1/2*y^-1",
+	},
+	{
+		"p(x^-1*(x + y))",
+		"1 + 1*x^-1*y",
+	},
+	{
+		"reduceterm(p(x + y), x^2 + 1, x)",
+		"1*y + -1*x^-1",
+	},
+	{
+		"contract([x*y^-1 - 1, y*z^-1 - 1])",
+		"<1*x + -1*z, 1*y + -1*z>",
+	},
+	{
+		"groebner([x - 2, x^-1 - 1])",
+		"[1]",
+	},
+	{
+		"dim([x^-1 - 2])",
+		"0",
+	},
+	{
+		"nsolve([x^-1 - 2], 5)",
+		"[[x = 0.5 ± 3.6e-24]]",
+	},
+	{
+		"groebner([x])",
+		"[1]",
+	},
+	{
+		"inradical(1, ideal([x]))",
+		"true",
+	},
+	{
+		"primdec(ideal([x^2*y - x]))",
+		"[[<1*x*y + -1> <1*x*y + -1>]]",
+	},
+	{
+		"standardmonomials(ideal([x^2*y - x, y^2 - 1]))",
+		"[1 1*y]",
+	},
+	{
+		"syzygies([x, y])",
+		"error: syzygies are not supported in mode(laurent)",
+	},
+	{
+		"mode(polynomial)",
+		"polynomial",
+	},
+	{
+		"gcd([x^4 - 1, x^6 - 1, x^3 - x^2 + x - 1])",
+		"1*x + -1",
+	},
+	{
+		"divmod(x^5 + 3*x + 1, 2*x^2 + 1)",
+		"[1/2*x^3 + -1/4*x 13/4*x + 1]",
+	},
+	{
+		"eval(x^3 - 2*x + 1, [0, 1, 2, -1/2, 5])",
+		"[1 0 5 15/8 116]",
+	},
+	{
+		"gcd([(x^70 - 1)*(x + 2), (x^70 - 1)*(x + 3)])",
+		"1*x^70 + -1",
+	},
+	{
+		"gcd([x^100 + x + 1, x^80 + 2])",
+		"1",
+	},
+	{
+		"divmod(x^200 + x + 1, x^70 - 2)",
+		"[1*x^130 + 2*x^60 4*x^60 + 1*x + 1]",
+	},
+	{
+		"eval(x^200 - 1, [" + strings.Repeat("1, -1, ", 32) + "0])",
+		"[" + strings.Repeat("0 0 ", 32) + "-1]",
+	},
+	{
+		"gcd([x^2 + 1, y])",
+		"error: polynomial 1*x^2 + 1 is not univariate",
+	},
+	{
+		"det([[x, y, 1], [y, x, z], [1, z, x]])",
+		"1*x^3 + -1*x*y^2 + -1*x*z^2 + -1*x + 2*y*z",
+	},
+	{
+		"rank([[1, 2, 3], [2, 4, 6], [1, 0, 1]])",
+		"2",
+	},
+	{
+		"rref([[1, 2, 3], [2, 4, 6], [1, 0, 1]])",
+		"[[1 0 1] [0 1 1] [0 0 0]]",
+	},
+	{
+		"inverse([[1, 2], [3, 4]])",
+		"[[-2 1] [3/2 -1/2]]",
+	},
+	{
+		"inverse([[x, 0], [0, 1]])",
+		"error: matrix is not invertible over the polynomial ring, the determinant is 1*x",
+	},
+	{
+		"nullspace([[x, y]])",
+		"[[-1*y 1*x]]",
+	},
+	{
+		"charpoly(multmatrix(ideal([x^2 - 2, y^2 - 3]), x + y), t)",
+		"1*t^4 + -10*t^2 + 1",
+	},
+	{
+		"nsolve([x^2 + y^2 - 1, x - y], 20)",
+		"[[x = -0.7071067811865475244 ± 9.3e-22 y = -0.7071067811865475244 ± 9.3e-22] [x = 0.7071067811865475244 ± 9.3e-22 y = 0.7071067811865475244 ± 9.3e-22]]",
+	},
+	{
+		"nsolve([x^3 - 1], 15)",
+		"[[x = 1 ± 5.5e-33] [x = -0.5 - 0.866025403784439*i ± 3.9e-16] [x = -0.5 + 0.866025403784439*i ± 3.9e-16]]",
+	},
+	{
+		"nsolve([x^2 - 1/100000000000000000000000000000000000000000000], 5)",
+		"[[x = -1e-22 ± 3.3e-69] [x = 1e-22 ± 3.3e-69]]",
+	},
+	{
+		"nsolve([x^2 - 2, y^2 - x], 10)",
+		"[[x = 1.414213562 ± 4.1e-10 y = -1.189207115 ± 3.0e-12] [x = 1.414213562 ± 4.1e-10 y = 1.189207115 ± 3.0e-12] [x = -1.414213562 ± 4.1e-10 y = 1.189207115*i ± 3.0e-12] [x = -1.414213562 ± 4.1e-10 y = -1.189207115*i ± 3.0e-12]]",
+	},
+	{
+		"nsolve([x^2 + 1], 5)",
+		"[[x = -1*i ± 4.0e-23] [x = 1*i ± 4.0e-23]]",
+	},
+	{
+		"nsolve([x*y - 1], 5)",
+		"error: nsolve requires a zero-dimensional ideal",
+	},
+	{
+		"(1 + i)^2",
+		"2*i",
+	},
+	{
+		"groebner([x^2 + x + 1, y + x + 1 + i])",
+		"[1*x + 1*y + (i + 1) 1*y^2 + (2*i + 1)*y + i]",
+	},
+	{
+		"factor(x^4 - 1)",
+		"[[1*x + 1 1] [1*x + -1 1] [1*x^2 + 1 1]]",
+	},
+	{
+		"factor(over(x^4 - 1, i))",
+		"[[1*x + i 1] [1*x + 1 1] [1*x + -1*i 1] [1*x + -1 1]]",
+	},
+	{
+		"factor(2*(x^2 + 1)*(x^2 + 1)*(x - 1))",
+		"[[2 1] [1*x + -1 1] [1*x^2 + 1 2]]",
+	},
+	{
+		"factor(over(x^4 + 1, i))",
+		"[[1*x^2 + i 1] [1*x^2 + -1*i 1]]",
+	},
+	{
+		"i = 3",
+		"error: syntax error: can not assign to i",
+	},
+	{
+		"field(i)",
+		"QQ(i)",
+	},
+	{
+		"factor(x^2 + 1)",
+		"[[1*x + -1*i 1] [1*x + i 1]]",
+	},
+	{
+		"minass([x + y, x*y - 1])",
+		"[<1*x + i, 1*y + -1*i> <1*x + -1*i, 1*y + i>]",
+	},
+	{
+		"minass([x + y + z + w, x*y + y*z + z*w + w*x, x*y*z + y*z*w + z*w*x + w*x*y, x*y*z*w - 1, x*z - 1])",
+		"[<1*w + -1*i, 1*x + i, 1*y + i, 1*z + -1*i> <1*w + i, 1*x + -1*i, 1*y + -1*i, 1*z + i> <1*w + -1*i, 1*x + -1*i, 1*y + i, 1*z + i> <1*w + i, 1*x + i, 1*y + -1*i, 1*z + -1*i>]",
+	},
+	{
+		"field(QQ)",
+		"QQ",
+	},
+	{
+		"factor(x^2 + 1)",
+		"[[1*x^2 + 1 1]]",
+	},
+	{
+		"issymmetric(x^2*y + x*y^2)",
+		"true",
+	},
+	{
+		"issymmetric(x^2 + y)",
+		"false",
+	},
+	{
+		"tosymmetric(x^3 + y^3)",
+		"1*e1^3 + -3*e1*e2",
+	},
+	{
+		"tosymmetric(x*y*z + x + y + z)",
+		"1*e1 + 1*e3",
+	},
+	{
+		"tosymmetric(x^2 + y)",
+		"error: polynomial 1*x^2 + 1*y is not symmetric",
+	},
+	{
+		"reynolds(x*y^2, [[y, z, x]])",
+		"1/3*x^2*z + 1/3*x*y^2 + 1/3*y*z^2",
+	},
+	{
+		"reynolds(over(x, gf(2, 1)), [[y, x]])",
+		"error: the order 2 of the group is divisible by the characteristic",
+	},
+	{
+		"\"modular\" + 1",
+		"error: syntax error: unexpected STRING",
+	},
+	{
+		"generator(gf(3, 2, b^2 + 1))^2",
+		"2",
+	},
+	{
+		"generator(gf(3, 2))/(generator(gf(3, 2)) - generator(gf(3, 2)))",
+		"error: division by zero",
+	},
+	{
+		"1/0",
+		"error: division by zero",
+	},
+	{
+		"gf(3, 2, b^2 + 2)",
+		"error: modulus 1*b^2 + 2 is not irreducible over GF(3)",
+	},
+	{
+		"field(2, x^2 - 2)",
+		"error: invalid generator 2, expected a name",
+	},
+	{
+		"field(r)",
+		"QQ[r]/(r^2 + -2)",
+	},
+	{
+		"factor(x^2 - 2)",
+		"[[1*x + -1*r 1] [1*x + r 1]]",
+	},
+	{
+		"field(QQ)",
+		"QQ",
+	},
+	{
+		"factor(x^2 - 2)",
+		"[[1*x^2 + -2 1]]",
+	},
+	{
+		"field(s, s^2 - 1)",
+		"error: modulus 1*s^2 + -1 is not irreducible",
+	},
+	{
+		"walk(groebner([1]), lex, grevlex)",
+		"[1]",
+	},
 }
 
 func TestBruno(t *testing.T) {
 	bruno := NewBruno()
 	for i := range brunoTests {
 		result, err := bruno.Exec(brunoTests[i].input)
+		var output string
 		if err != nil {
-			t.Errorf("test %q: unexpected error %v.", brunoTests[i].input, err)
-			continue
+			output = "error: " + err.Error()
+		} else {
+			output = result.String()
 		}
-		if output := result.String(); output != brunoTests[i].output {
+		if output != brunoTests[i].output {
 			t.Errorf("test %q: expected output %q, got %q.",
 				brunoTests[i].input, brunoTests[i].output, output)
 		}
@@ -0,0 +1,256 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Matrix is a matrix of polynomials, which share the same variables, term
+// order and field. Matrices with constant entries are matrices over the
+// field. The linear algebra is done with fraction-free elimination, so all
+// intermediate results are polynomials again.
+type Matrix struct {
+	vars  []string
+	order TermOrder
+	field Field
+	rows  [][]*Polynomial
+	cols  int
+}
+
+func (M *Matrix) String() string {
+	rows := make(List, len(M.rows))
+	for i := range M.rows {
+		rows[i] = polynomialList(M.rows[i])
+	}
+	return rows.String()
+}
+
+// constant returns the constant polynomial c in the ring of M.
+func (M *Matrix) constant(c Coeff) *Polynomial {
+	p := &Polynomial{vars: M.vars, order: M.order, field: M.field}
+	if !M.field.IsZero(c) {
+		p.items = []Monomial{{c, NewTerm(len(M.vars))}}
+	}
+	return p
+}
+
+func (M *Matrix) one() *Polynomial {
+	return M.constant(M.field.One())
+}
+
+// entries returns a copy of the entries of M.
+func (M *Matrix) entries() [][]*Polynomial {
+	a := make([][]*Polynomial, len(M.rows))
+	for i := range M.rows {
+		a[i] = append([]*Polynomial{}, M.rows[i]...)
+	}
+	return a
+}
+
+// isConstant reports whether p is a constant polynomial.
+func isConstant(p *Polynomial) bool {
+	return len(p.items) == 0 ||
+		(len(p.items) == 1 && termEqual(p.items[0].T, NewTerm(len(p.vars))))
+}
+
+// exactQuo divides p by q, which must divide p.
+func exactQuo(p, q *Polynomial) *Polynomial {
+	if isConstant(q) {
+		return p.MulMonomial(p.field.Quo(p.field.One(), q.items[0].C), q.items[0].T)
+	}
+	quo, _ := p.divide([]*Polynomial{q})
+	return quo[0]
+}
+
+// fractionFree transforms a into a fraction-free reduced row echelon form
+// with Bareiss' method. In every step, all rows except the pivot row r are
+// replaced by (a_rc*a_ij - a_ic*a_rj) / d, where d is the previous pivot.
+// The divisions are exact, all pivots of the result are equal to the last
+// pivot d, and all other entries of the pivot columns vanish, so a/d is the
+// reduced row echelon form. If a is square and invertible, sign*d is its
+// determinant, where sign is the sign of the row permutation.
+func fractionFree(a [][]*Polynomial, cols int, one *Polynomial) (pivots []int, d *Polynomial, sign int) {
+	d, sign = one, 1
+	r := 0
+	for c := 0; c < cols && r < len(a); c++ {
+		p := -1
+		for i := r; i < len(a); i++ {
+			if !a[i][c].IsZero() && (p < 0 || len(a[i][c].items) < len(a[p][c].items)) {
+				p = i
+			}
+		}
+		if p < 0 {
+			continue
+		}
+		if p != r {
+			a[p], a[r] = a[r], a[p]
+			sign = -sign
+		}
+		for i := range a {
+			if i == r {
+				continue
+			}
+			for j := 0; j < cols; j++ {
+				if j == c {
+					continue
+				}
+				h := a[r][c].Mul(a[i][j]).Sub(a[i][c].Mul(a[r][j]))
+				a[i][j] = exactQuo(h, d)
+			}
+			a[i][c] = &Polynomial{vars: one.vars, order: one.order, field: one.field}
+		}
+		d = a[r][c]
+		pivots = append(pivots, c)
+		r++
+	}
+	return pivots, d, sign
+}
+
+// Det calculates the determinant of the square matrix M.
+func (M *Matrix) Det() (*Polynomial, error) {
+	if len(M.rows) != M.cols {
+		return nil, errors.New("det requires a square matrix")
+	}
+	pivots, d, sign := fractionFree(M.entries(), M.cols, M.one())
+	if len(pivots) < M.cols {
+		return M.constant(M.field.Zero()), nil
+	}
+	if sign < 0 {
+		d = M.constant(M.field.Zero()).Sub(d)
+	}
+	return d, nil
+}
+
+// Rank returns the rank of M over the field of fractions.
+func (M *Matrix) Rank() int {
+	pivots, _, _ := fractionFree(M.entries(), M.cols, M.one())
+	return len(pivots)
+}
+
+// RREF returns the reduced row echelon form of M. If the entries are not
+// constant, the division by the last pivot is not possible in general and
+// the fraction-free form is returned instead, in which all pivots are equal
+// to this common denominator.
+func (M *Matrix) RREF() *Matrix {
+	a := M.entries()
+	_, d, _ := fractionFree(a, M.cols, M.one())
+	if isConstant(d) {
+		for i := range a {
+			for j := range a[i] {
+				a[i][j] = exactQuo(a[i][j], d)
+			}
+		}
+	}
+	return &Matrix{vars: M.vars, order: M.order, field: M.field, rows: a, cols: M.cols}
+}
+
+// Inverse calculates the inverse of the square matrix M by reducing the
+// matrix (M | 1). The inverse only exists in the polynomial ring if the
+// determinant is a non-zero constant.
+func (M *Matrix) Inverse() (*Matrix, error) {
+	n := M.cols
+	if len(M.rows) != n {
+		return nil, errors.New("inverse requires a square matrix")
+	}
+	a := make([][]*Polynomial, n)
+	for i := range a {
+		a[i] = append([]*Polynomial{}, M.rows[i]...)
+		for j := 0; j < n; j++ {
+			if i == j {
+				a[i] = append(a[i], M.one())
+			} else {
+				a[i] = append(a[i], M.constant(M.field.Zero()))
+			}
+		}
+	}
+	pivots, d, _ := fractionFree(a, 2*n, M.one())
+	if len(pivots) < n || pivots[n-1] != n-1 {
+		return nil, errors.New("matrix is singular")
+	}
+	if !isConstant(d) {
+		return nil, fmt.Errorf("matrix is not invertible over the polynomial ring, the determinant is %v", d)
+	}
+	rval := &Matrix{vars: M.vars, order: M.order, field: M.field, cols: n}
+	for i := range a {
+		row := make([]*Polynomial, n)
+		for j := range row {
+			row[j] = exactQuo(a[i][n+j], d)
+		}
+		rval.rows = append(rval.rows, row)
+	}
+	return rval, nil
+}
+
+// Nullspace calculates a basis of the vectors v with M*v = 0. For every
+// non-pivot column j of the fraction-free reduced row echelon form R with
+// pivots d, the vector with d at position j and -R_ij at the position of the
+// pivot of row i is a solution. The vectors are divided by d if it is
+// constant.
+func (M *Matrix) Nullspace() [][]*Polynomial {
+	a := M.entries()
+	pivots, d, _ := fractionFree(a, M.cols, M.one())
+	isPivot := make(map[int]bool)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+	var basis [][]*Polynomial
+	for j := 0; j < M.cols; j++ {
+		if isPivot[j] {
+			continue
+		}
+		v := make([]*Polynomial, M.cols)
+		for k := range v {
+			v[k] = M.constant(M.field.Zero())
+		}
+		v[j] = d
+		for i, c := range pivots {
+			v[c] = v[c].Sub(a[i][j])
+		}
+		if isConstant(d) {
+			for k := range v {
+				v[k] = exactQuo(v[k], d)
+			}
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+// Charpoly calculates the characteristic polynomial det(t*1 - M) of the
+// square matrix M in the new variable t.
+func (M *Matrix) Charpoly(t string) (*Polynomial, error) {
+	if len(M.rows) != M.cols {
+		return nil, errors.New("charpoly requires a square matrix")
+	}
+	for _, v := range M.vars {
+		if v == t {
+			return nil, fmt.Errorf("variable %s already occurs in the matrix", t)
+		}
+	}
+	vars := append(append([]string{}, M.vars...), t)
+	sort.Strings(vars)
+	k := sort.SearchStrings(vars, t)
+	N := &Matrix{vars: vars, order: M.order, field: M.field, cols: M.cols}
+	x := N.constant(M.field.One())
+	x.items[0].T = x.items[0].T.addExp(k, big.NewRat(1, 1))
+	for i := range M.rows {
+		row := make([]*Polynomial, M.cols)
+		for j, f := range M.rows[i] {
+			g, err := f.embed(vars, M.order)
+			if err != nil {
+				return nil, err
+			}
+			row[j] = N.constant(M.field.Zero()).Sub(g)
+			if i == j {
+				row[j] = row[j].Add(x)
+			}
+		}
+		N.rows = append(N.rows, row)
+	}
+	return N.Det()
+}
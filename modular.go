@@ -0,0 +1,243 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// maxModularPrimes limits the number of primes used by GroebnerModular
+// before it gives up.
+const maxModularPrimes = 1000
+
+// modularImage collects the images of a Gröbner basis modulo several primes
+// which share the same leading terms. The coefficients are combined with the
+// chinese remainder theorem.
+type modularImage struct {
+	vars   []string
+	order  TermOrder
+	count  int
+	modulo *big.Int
+	polys  []map[string]*crtCoeff
+	last   []*Polynomial
+}
+
+type crtCoeff struct {
+	t Term
+	c *big.Int
+}
+
+// GroebnerModular calculates the reduced Gröbner basis of fns over the
+// rationals. The basis is computed modulo several primes, the images are
+// combined with the chinese remainder theorem and lifted to the rationals by
+// rational reconstruction. Images whose leading terms disagree with the
+// majority are considered unlucky and are discarded. As soon as the lifted
+// basis does not change anymore, it is verified by checking that it is a
+// Gröbner basis of the same ideal.
+func GroebnerModular(fns []*Polynomial) ([]*Polynomial, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	for _, f := range fns {
+		if f.field != Rationals {
+			return nil, errors.New("modular algorithm requires rational coefficients")
+		}
+	}
+	images := make(map[string]*modularImage)
+	prime := uint64(1<<31 - 1)
+	for n := 0; n < maxModularPrimes; n++ {
+		prime = prevPrime(prime)
+		basis, ok := groebnerModPrime(fns, prime)
+		if !ok {
+			continue
+		}
+		key := basisKey(basis)
+		img := images[key]
+		if img == nil {
+			img = &modularImage{vars: fns[0].vars, order: fns[0].order,
+				modulo: big.NewInt(1)}
+			images[key] = img
+		}
+		img.combine(basis, prime)
+		majority := true
+		for _, other := range images {
+			if other.count > img.count {
+				majority = false
+			}
+		}
+		if !majority {
+			continue
+		}
+		lifted, ok := img.lift()
+		if !ok {
+			continue
+		}
+		if img.last != nil && basisEqual(lifted, img.last) &&
+			verifyBasis(fns, lifted) {
+			return lifted, nil
+		}
+		img.last = lifted
+	}
+	return nil, errors.New("modular algorithm did not converge")
+}
+
+// prevPrime returns the largest prime smaller than n.
+func prevPrime(n uint64) uint64 {
+	x := new(big.Int)
+	for n--; n > 2; n-- {
+		if x.SetUint64(n).ProbablyPrime(20) {
+			return n
+		}
+	}
+	return 2
+}
+
+// groebnerModPrime calculates the Gröbner basis of fns modulo prime. Primes
+// dividing a denominator or a leading coefficient of the input are rejected.
+func groebnerModPrime(fns []*Polynomial, prime uint64) ([]*Polynomial, bool) {
+	field, err := NewPrimeField(prime)
+	if err != nil {
+		return nil, false
+	}
+	images := make([]*Polynomial, 0, len(fns))
+	for _, f := range fns {
+		g, err := f.mapCoeffs(field, func(c Coeff) (Coeff, error) {
+			return field.FromRat(c.(*big.Rat))
+		})
+		if err != nil || len(g.items) != len(f.items) && (len(g.items) == 0 ||
+			!termEqual(g.items[0].T, f.items[0].T)) {
+			return nil, false
+		}
+		images = append(images, g)
+	}
+	return Groebner(images), true
+}
+
+// basisKey returns a string identifying the leading terms of a basis.
+func basisKey(basis []*Polynomial) string {
+	keys := make([]string, len(basis))
+	for i := range basis {
+		keys[i] = termKey(basis[i].items[0].T)
+	}
+	return strings.Join(keys, ";")
+}
+
+// combine adds the basis, which was calculated modulo prime, to the image.
+func (img *modularImage) combine(basis []*Polynomial, prime uint64) {
+	p := new(big.Int).SetUint64(prime)
+	if img.polys == nil {
+		img.polys = make([]map[string]*crtCoeff, len(basis))
+		for i := range img.polys {
+			img.polys[i] = make(map[string]*crtCoeff)
+		}
+	}
+	// inv is the inverse of the previous modulo in Z/pZ
+	inv := new(big.Int).ModInverse(new(big.Int).Mod(img.modulo, p), p)
+	for i, f := range basis {
+		residues := make(map[string]uint64, len(f.items))
+		for _, m := range f.items {
+			key := termKey(m.T)
+			residues[key] = m.C.(uint64)
+			if img.polys[i][key] == nil {
+				img.polys[i][key] = &crtCoeff{m.T, new(big.Int)}
+			}
+		}
+		for key, x := range img.polys[i] {
+			// x.c = x.c + modulo * ((r - x.c) * inv mod p)
+			d := new(big.Int).SetUint64(residues[key])
+			d.Sub(d, x.c)
+			d.Mul(d, inv)
+			d.Mod(d, p)
+			d.Mul(d, img.modulo)
+			x.c.Add(x.c, d)
+		}
+	}
+	img.modulo.Mul(img.modulo, p)
+	img.count++
+}
+
+// lift reconstructs rational coefficients from the combined residues.
+func (img *modularImage) lift() ([]*Polynomial, bool) {
+	basis := make([]*Polynomial, len(img.polys))
+	for i := range img.polys {
+		p := &Polynomial{vars: img.vars, order: img.order, field: Rationals}
+		for _, x := range img.polys[i] {
+			r, ok := ratReconstruct(x.c, img.modulo)
+			if !ok {
+				return nil, false
+			}
+			if r.Sign() != 0 {
+				p.items = append(p.items, Monomial{r, x.t})
+			}
+		}
+		p.normalize()
+		basis[i] = p
+	}
+	sort.Sort(basisSorter(basis))
+	return basis, true
+}
+
+// ratReconstruct finds a rational number r/s with r = a*s mod m and
+// |r|, s <= sqrt(m/2) using the extended euclidean algorithm.
+func ratReconstruct(a, m *big.Int) (*big.Rat, bool) {
+	bound := new(big.Int).Rsh(m, 1)
+	bound.Sqrt(bound)
+	r0, r1 := new(big.Int).Set(m), new(big.Int).Mod(a, m)
+	s0, s1 := big.NewInt(0), big.NewInt(1)
+	q, t := new(big.Int), new(big.Int)
+	for r1.Cmp(bound) > 0 {
+		q.Quo(r0, r1)
+		t.Mul(q, r1)
+		r0, r1 = r1, t.Sub(r0, t)
+		t = new(big.Int)
+		t.Mul(q, s1)
+		s0, s1 = s1, t.Sub(s0, t)
+		t = new(big.Int)
+	}
+	if s1.Sign() == 0 || new(big.Int).Abs(s1).Cmp(bound) > 0 {
+		return nil, false
+	}
+	if new(big.Int).GCD(nil, nil, r1, new(big.Int).Abs(s1)).Cmp(big.NewInt(1)) != 0 {
+		return nil, false
+	}
+	return new(big.Rat).SetFrac(r1, s1), true
+}
+
+func basisEqual(a, b []*Polynomial) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyBasis checks that basis is a Gröbner basis of the ideal generated
+// by fns. Every polynomial of fns must be contained in the ideal generated by
+// basis, and conversely every element of basis must reduce to zero modulo a
+// Gröbner basis of fns, otherwise a wrong rational reconstruction might
+// generate a bigger ideal. The cheap checks are done first.
+func verifyBasis(fns, basis []*Polynomial) bool {
+	for _, f := range fns {
+		if !f.NormalForm(basis).IsZero() {
+			return false
+		}
+	}
+	if !isGroebner(basis) {
+		return false
+	}
+	gb := Groebner(fns)
+	for _, g := range basis {
+		if !g.NormalForm(gb).IsZero() {
+			return false
+		}
+	}
+	return true
+}
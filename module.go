@@ -0,0 +1,198 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// freeModule is the free module R^rank over a polynomial ring. A vector
+// sum f_i*e_i is stored as a polynomial in the variables e_1, ..., e_rank
+// followed by the variables of the ring, in which every term contains
+// exactly one of the position variables. Divisibility of terms is then
+// divisibility of module terms, so the normal form and the reduction of
+// polynomials work for vectors without changes.
+type freeModule struct {
+	vars   []string
+	order  TermOrder
+	field  Field
+	rank   int
+	mvars  []string
+	morder TermOrder
+}
+
+// newFreeModule returns the free module of the given rank. If pot is true,
+// vectors are compared position over term, i.e. by the position first and
+// e_1 is the largest position, otherwise term over position.
+func newFreeModule(vars []string, order TermOrder, field Field, rank int, pot bool) *freeModule {
+	M := &freeModule{vars: vars, order: order, field: field, rank: rank,
+		morder: moduleOrder(rank, order, pot)}
+	for i := 0; i < rank; i++ {
+		// the names can not clash with user variables
+		M.mvars = append(M.mvars, fmt.Sprintf("@e%d", i+1))
+	}
+	M.mvars = append(M.mvars, vars...)
+	return M
+}
+
+// position returns the index of the position variable of a module term.
+func position(t Term, rank int) int {
+	for i := 0; i < rank; i++ {
+		if t.Sign(i) != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// moduleOrder extends the term order of the ring to module terms.
+func moduleOrder(rank int, order TermOrder, pot bool) TermOrder {
+	return func(a, b Term) bool {
+		idx := make([]int, a.Len()-rank)
+		for i := range idx {
+			idx[i] = rank + i
+		}
+		ta, tb := a.remap(idx), b.remap(idx)
+		pa, pb := position(a, rank), position(b, rank)
+		if pot && pa != pb {
+			return pa > pb
+		}
+		if order(ta, tb) {
+			return true
+		}
+		if order(tb, ta) {
+			return false
+		}
+		return pa > pb
+	}
+}
+
+// vector combines the components into a vector.
+func (M *freeModule) vector(comps []*Polynomial) (*Polynomial, error) {
+	if len(comps) != M.rank {
+		return nil, fmt.Errorf("vector needs %d components", M.rank)
+	}
+	v := &Polynomial{vars: M.mvars, order: M.morder, field: M.field}
+	idx := make([]int, len(M.mvars))
+	for i := range idx {
+		idx[i] = i - M.rank
+	}
+	for i, f := range comps {
+		if f.field != M.field {
+			return nil, fmt.Errorf("incompatible fields %v and %v", f.field, M.field)
+		}
+		g, err := f.embed(M.vars, M.order)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range g.items {
+			t := m.T.remap(idx).addExp(i, big.NewRat(1, 1))
+			v.items = append(v.items, Monomial{m.C, t})
+		}
+	}
+	v.normalize()
+	return v, nil
+}
+
+// components splits a vector into its components.
+func (M *freeModule) components(v *Polynomial) []*Polynomial {
+	comps := make([]*Polynomial, M.rank)
+	for i := range comps {
+		comps[i] = &Polynomial{vars: M.vars, order: M.order, field: M.field}
+	}
+	idx := make([]int, len(M.vars))
+	for i := range idx {
+		idx[i] = M.rank + i
+	}
+	for _, m := range v.items {
+		i := position(m.T, M.rank)
+		comps[i].items = append(comps[i].items, Monomial{m.C, m.T.remap(idx)})
+	}
+	for _, f := range comps {
+		f.normalize()
+	}
+	return comps
+}
+
+// GroebnerModule calculates the reduced Gröbner basis of the submodule
+// generated by the vectors fns with Buchberger's algorithm. Only pairs of
+// vectors whose leading terms have the same position are considered.
+func (M *freeModule) GroebnerModule(fns []*Polynomial) []*Polynomial {
+	var (
+		basis []*Polynomial
+		pairs []critPair
+	)
+	add := func(f *Polynomial) {
+		basis = append(basis, f.Monic())
+		k := len(basis) - 1
+		for i := 0; i < k; i++ {
+			a, b := basis[i].items[0].T, basis[k].items[0].T
+			if position(a, M.rank) == position(b, M.rank) {
+				pairs = append(pairs, critPair{i, k, termLCM(a, b)})
+			}
+		}
+	}
+	for _, f := range fns {
+		if !f.IsZero() {
+			add(f)
+		}
+	}
+	for len(pairs) > 0 {
+		sel := 0
+		for k := range pairs {
+			if M.morder(pairs[k].lcm, pairs[sel].lcm) {
+				sel = k
+			}
+		}
+		pair := pairs[sel]
+		pairs = append(pairs[:sel], pairs[sel+1:]...)
+		if skipPair(basis, pairs, pair) {
+			continue
+		}
+		h := SPolynomial(basis[pair.i], basis[pair.j]).NormalForm(basis)
+		if !h.IsZero() {
+			add(h)
+		}
+	}
+	return reduceBasis(basis)
+}
+
+// Syzygies calculates generators of the module of syzygies of fns, the
+// vectors (s_1, ..., s_n) with s_1*f_1 + ... + s_n*f_n = 0. The Gröbner
+// basis of the vectors (f_i, e_i) in R^(n+1) is calculated with respect to
+// a position over term order, in which the first position is the largest.
+// Its elements whose first component vanishes generate the syzygies.
+func Syzygies(fns []*Polynomial) ([][]*Polynomial, error) {
+	if len(fns) == 0 {
+		return nil, errors.New("syzygies requires at least one polynomial")
+	}
+	n := len(fns)
+	proto := fns[0]
+	M := newFreeModule(proto.vars, proto.order, proto.field, n+1, true)
+	zero := &Polynomial{vars: proto.vars, order: proto.order, field: proto.field}
+	one := &Polynomial{vars: proto.vars, order: proto.order, field: proto.field,
+		items: []Monomial{{proto.field.One(), NewTerm(len(proto.vars))}}}
+	gens := make([]*Polynomial, n)
+	for i, f := range fns {
+		comps := make([]*Polynomial, n+1)
+		for j := range comps {
+			comps[j] = zero
+		}
+		comps[0], comps[i+1] = f, one
+		v, err := M.vector(comps)
+		if err != nil {
+			return nil, err
+		}
+		gens[i] = v
+	}
+	var syz [][]*Polynomial
+	for _, g := range M.GroebnerModule(gens) {
+		if position(g.items[0].T, M.rank) != 0 {
+			syz = append(syz, M.components(g)[1:])
+		}
+	}
+	return syz, nil
+}
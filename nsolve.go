@@ -0,0 +1,448 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Approx is a certified approximation of a complex number: the exact value
+// lies in the closed disk with the radius err around re + im*i. The
+// approximation is printed with the given number of significant digits.
+type Approx struct {
+	re, im, err *big.Float
+	digits      int
+}
+
+// newApprox rounds the center to the number of significant digits and
+// enlarges the error bound by the rounding error. Real and imaginary parts
+// which are smaller than the error bound are set to zero.
+func newApprox(z bigComplex, err *big.Float, digits int) Approx {
+	prec := z.re.Prec()
+	a := Approx{digits: digits, err: new(big.Float).SetPrec(prec).Set(err)}
+	round := func(x *big.Float) *big.Float {
+		if abs := new(big.Float).Abs(x); abs.Cmp(err) <= 0 {
+			a.err.Add(a.err, abs)
+			return new(big.Float).SetPrec(prec)
+		}
+		y, _, _ := new(big.Float).SetPrec(prec).Parse(x.Text('g', digits), 10)
+		d := new(big.Float).SetPrec(prec).Sub(x, y)
+		a.err.Add(a.err, d.Abs(d))
+		return y
+	}
+	a.re, a.im = round(z.re), round(z.im)
+	return a
+}
+
+func (a Approx) String() string {
+	// two significant digits of the error bound, rounded up
+	e := new(big.Float).Mul(a.err, big.NewFloat(1.1))
+	bound := " ± " + e.Text('e', 1)
+	if a.im.Sign() == 0 {
+		return a.re.Text('g', a.digits) + bound
+	}
+	if a.re.Sign() == 0 {
+		return a.im.Text('g', a.digits) + "*i" + bound
+	}
+	sign := " + "
+	im := new(big.Float).Set(a.im)
+	if im.Sign() < 0 {
+		sign = " - "
+		im.Neg(im)
+	}
+	return a.re.Text('g', a.digits) + sign + im.Text('g', a.digits) + "*i" + bound
+}
+
+// NSolve approximates the solutions of the zero-dimensional ideal I over
+// the rationals with the given number of significant digits. A linear form
+// l is searched which separates the solutions, like for the primary
+// decomposition. The lexicographical Gröbner basis of rad(I) + <t - l> with
+// t as smallest variable is then in shape position, i.e. it consists of the
+// square free minimal polynomial u(t) of l and of x_i - v_i(t) for every
+// variable x_i. The roots of u are calculated with Aberth's method and the
+// solutions are x_i = v_i(t) for every root t.
+//
+// The error bounds are certified: if z_1, ..., z_n approximate all roots
+// of u, the disks around z_k with the radii n*|u(z_k)| / |lc(u)*prod (z_k -
+// z_j)| contain all roots, and each disk which is disjoint from the others
+// contains exactly one root. If the disks overlap, the roots are calculated
+// again with a higher precision. The bounds are propagated to x_i by bounding
+// the derivative of v_i on the disk. A root is real if the disk around its
+// real part with twice the radius is disjoint from the other disks, since
+// the roots of u come in conjugate pairs.
+func NSolve(I *Ideal, digits int) ([][]Approx, error) {
+	if I.field != Rationals {
+		return nil, errors.New("nsolve requires rational coefficients")
+	}
+	basis := I.Basis()
+	if len(basis) == 0 || !isZeroDimensional(basis) {
+		return nil, errors.New("nsolve requires a zero-dimensional ideal")
+	}
+	if basis[0].items[0].T.Degree().Sign() == 0 {
+		return nil, nil
+	}
+	rad, err := Radical(I)
+	if err != nil {
+		return nil, err
+	}
+	q, err := newQuotientRing(rad)
+	if err != nil {
+		return nil, err
+	}
+	// every pair of the d solutions is only mapped to the same value by
+	// n-1 of the forms x_1 + k*x_2 + ... + k^(n-1)*x_n
+	n, d := int64(len(I.vars)), int64(len(q.terms))
+	var l *Polynomial
+	var u []Coeff
+	for c := int64(0); upolyDegree(u) != len(q.terms); c++ {
+		if c > n+(n-1)*d*d {
+			return nil, errors.New("no separating linear form found")
+		}
+		l = separatingForm(I, len(I.vars), c)
+		u = upolySquarefree(Rationals, minimalPolynomialOf(basis, l))
+	}
+	shape, err := shapeBasis(rad, l)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []bigComplex
+	var radii []*big.Float
+	var isReal []bool
+	prec := uint(float64(digits)*math.Log2(10)) + 64
+	for k := 0; ; k++ {
+		a := make([]*big.Float, len(u))
+		for i := range u {
+			a[i] = new(big.Float).SetPrec(prec).SetRat(u[i].(*big.Rat))
+		}
+		roots, radii, isReal, err = certifiedRoots(a, prec)
+		if err == nil {
+			break
+		} else if k == maxPrecisionSteps {
+			return nil, err
+		}
+		prec *= 2
+	}
+	perm := make([]int, len(roots))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.Sort(rootSorter{perm, roots, isReal})
+
+	var rval [][]Approx
+	for _, k := range perm {
+		sol := make([]Approx, len(shape))
+		for i, v := range shape {
+			c := make([]*big.Float, len(v))
+			for j := range v {
+				c[j] = new(big.Float).SetPrec(prec).SetRat(v[j].(*big.Rat))
+			}
+			val, _ := complexHorner(prec, c, roots[k])
+			sol[i] = newApprox(val, propagateBound(prec, c, roots[k], radii[k]), digits)
+		}
+		rval = append(rval, sol)
+	}
+	return rval, nil
+}
+
+// shapeBasis calculates the polynomials v_i with x_i = v_i(l) modulo the
+// radical ideal rad, in which l separates the solutions.
+func shapeBasis(rad *Ideal, l *Polynomial) ([][]Coeff, error) {
+	n := len(rad.vars)
+	// the name can not clash with user variables and is the first one
+	vars := append([]string{"@t"}, rad.vars...)
+	m := make(matrixOrder, n+1)
+	for i := range m {
+		m[i] = make([]*big.Rat, n+1)
+		for j := range m[i] {
+			m[i][j] = new(big.Rat)
+		}
+		m[i][(i+1)%(n+1)].SetInt64(1)
+	}
+	order := m.TermOrder()
+	t := &Polynomial{vars: vars, order: order, field: rad.field,
+		items: []Monomial{{big.NewRat(1, 1), NewTerm(n+1).addExp(0, ratOne)}}}
+	g, err := l.embed(vars, order)
+	if err != nil {
+		return nil, err
+	}
+	fns := []*Polynomial{t.Sub(g)}
+	for _, f := range rad.gens {
+		if g, err = f.embed(vars, order); err != nil {
+			return nil, err
+		}
+		fns = append(fns, g)
+	}
+	shape := make([][]Coeff, n)
+	for _, g := range Groebner(fns) {
+		// the variable x_i of the leading term, which is t for u(t)
+		i := 1
+		for i <= n && g.items[0].T.Sign(i) == 0 {
+			i++
+		}
+		if i > n {
+			continue
+		}
+		v := []Coeff{}
+		for _, m := range g.items[1:] {
+			e, ok := m.T.Int(0)
+			for j := 1; j <= n; j++ {
+				ok = ok && m.T.Sign(j) == 0
+			}
+			if !ok {
+				return nil, fmt.Errorf("basis element %v is not in shape position", g)
+			}
+			for len(v) <= e {
+				v = append(v, big.NewRat(0, 1))
+			}
+			v[e] = new(big.Rat).Neg(m.C.(*big.Rat))
+		}
+		shape[i-1] = v
+	}
+	for i := range shape {
+		if shape[i] == nil {
+			return nil, fmt.Errorf("no shape basis for %s", rad.vars[i])
+		}
+	}
+	return shape, nil
+}
+
+// rootSorter orders the real roots before the complex ones, by their real
+// parts first and by their imaginary parts afterwards.
+type rootSorter struct {
+	perm   []int
+	roots  []bigComplex
+	isReal []bool
+}
+
+func (s rootSorter) Less(i, j int) bool {
+	a, b := s.perm[i], s.perm[j]
+	if s.isReal[a] != s.isReal[b] {
+		return s.isReal[a]
+	}
+	if x := s.roots[a].re.Cmp(s.roots[b].re); x != 0 {
+		return x < 0
+	}
+	return s.roots[a].im.Cmp(s.roots[b].im) < 0
+}
+
+func (s rootSorter) Swap(i, j int) {
+	s.perm[i], s.perm[j] = s.perm[j], s.perm[i]
+}
+
+func (s rootSorter) Len() int {
+	return len(s.perm)
+}
+
+// bigComplex is a complex number with arbitrary precision.
+type bigComplex struct {
+	re, im *big.Float
+}
+
+func newComplex(prec uint) bigComplex {
+	return bigComplex{new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)}
+}
+
+func (z bigComplex) add(prec uint, w bigComplex) bigComplex {
+	r := newComplex(prec)
+	r.re.Add(z.re, w.re)
+	r.im.Add(z.im, w.im)
+	return r
+}
+
+func (z bigComplex) sub(prec uint, w bigComplex) bigComplex {
+	r := newComplex(prec)
+	r.re.Sub(z.re, w.re)
+	r.im.Sub(z.im, w.im)
+	return r
+}
+
+func (z bigComplex) mul(prec uint, w bigComplex) bigComplex {
+	r, t := newComplex(prec), newComplex(prec)
+	r.re.Mul(z.re, w.re)
+	t.re.Mul(z.im, w.im)
+	r.re.Sub(r.re, t.re)
+	r.im.Mul(z.re, w.im)
+	t.im.Mul(z.im, w.re)
+	r.im.Add(r.im, t.im)
+	return r
+}
+
+func (z bigComplex) quo(prec uint, w bigComplex) bigComplex {
+	d, t := new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)
+	d.Mul(w.re, w.re)
+	d.Add(d, t.Mul(w.im, w.im))
+	r := z.mul(prec, bigComplex{w.re, new(big.Float).Neg(w.im)})
+	r.re.Quo(r.re, d)
+	r.im.Quo(r.im, d)
+	return r
+}
+
+func (z bigComplex) abs(prec uint) *big.Float {
+	r, t := new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)
+	r.Mul(z.re, z.re)
+	r.Add(r, t.Mul(z.im, z.im))
+	return r.Sqrt(r)
+}
+
+func (z bigComplex) isZero() bool {
+	return z.re.Sign() == 0 && z.im.Sign() == 0
+}
+
+// complexHorner evaluates the polynomial with the real coefficients a and
+// its derivative at z.
+func complexHorner(prec uint, a []*big.Float, z bigComplex) (v, d bigComplex) {
+	v, d = newComplex(prec), newComplex(prec)
+	for i := len(a) - 1; i >= 0; i-- {
+		d = d.mul(prec, z).add(prec, v)
+		v = v.mul(prec, z)
+		v.re.Add(v.re, a[i])
+	}
+	return v, d
+}
+
+// absHorner evaluates sum |a_k|*x^k and the derivative sum k*|a_k|*x^(k-1)
+// at the non-negative x.
+func absHorner(prec uint, a []*big.Float, x *big.Float) (v, d *big.Float) {
+	v, d = new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)
+	c := new(big.Float).SetPrec(prec)
+	for i := len(a) - 1; i >= 0; i-- {
+		d.Mul(d, x)
+		d.Add(d, v)
+		v.Mul(v, x)
+		v.Add(v, c.Abs(a[i]))
+	}
+	return v, d
+}
+
+// roundingError bounds the rounding error of the evaluation of the
+// polynomial a with Horner's scheme at a point with absolute value x.
+func roundingError(prec uint, a []*big.Float, x *big.Float) *big.Float {
+	s, _ := absHorner(prec, a, x)
+	s.Mul(s, big.NewFloat(float64(4*len(a)+4)))
+	return s.SetMantExp(s, -int(prec))
+}
+
+// propagateBound bounds |v(z) - v(w)| for all w in the disk around z with
+// radius r by r times the maximum of |v'| on the disk, and adds the
+// rounding error of the evaluation.
+func propagateBound(prec uint, v []*big.Float, z bigComplex, r *big.Float) *big.Float {
+	x := z.abs(prec)
+	_, d := absHorner(prec, v, new(big.Float).Add(x, r))
+	d.Mul(d, r)
+	return d.Add(d, roundingError(prec, v, x))
+}
+
+// aberth approximates all roots of the square free polynomial with the real
+// coefficients a simultaneously with Aberth's method. Every approximation z_k
+// is corrected by w/(1 - w*sum 1/(z_k - z_j)), where w = p(z_k)/p'(z_k) is
+// the Newton correction, which converges cubically to simple roots.
+func aberth(prec uint, a []*big.Float) []bigComplex {
+	n := len(a) - 1
+	// all roots lie in the disk with the radius 1 + max |a_i/a_n| (Cauchy)
+	bound := new(big.Float).SetPrec(prec)
+	for i := 0; i < n; i++ {
+		q := new(big.Float).SetPrec(prec).Quo(a[i], a[n])
+		if q.Abs(q).Cmp(bound) > 0 {
+			bound = q
+		}
+	}
+	R, _ := bound.Float64()
+	R = math.Min(R+1, 1e300)
+	z := make([]bigComplex, n)
+	for k := range z {
+		phi := 2*math.Pi*float64(k)/float64(n) + 0.4
+		z[k] = newComplex(prec)
+		z[k].re.SetFloat64(R * math.Cos(phi))
+		z[k].im.SetFloat64(R * math.Sin(phi))
+	}
+	one := newComplex(prec)
+	one.re.SetInt64(1)
+	tol := new(big.Float).SetMantExp(big.NewFloat(1), 16-int(prec))
+	for iter := 0; iter < 100*n+int(prec); iter++ {
+		done := true
+		for k := range z {
+			p, dp := complexHorner(prec, a, z[k])
+			if p.isZero() || dp.isZero() {
+				continue
+			}
+			w := p.quo(prec, dp)
+			s := newComplex(prec)
+			for j := range z {
+				if j != k {
+					s = s.add(prec, one.quo(prec, z[k].sub(prec, z[j])))
+				}
+			}
+			corr := w.quo(prec, one.sub(prec, w.mul(prec, s)))
+			z[k] = z[k].sub(prec, corr)
+			lim := new(big.Float).Add(big.NewFloat(1), z[k].abs(prec))
+			if corr.abs(prec).Cmp(lim.Mul(lim, tol)) > 0 {
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+	return z
+}
+
+// maxPrecisionSteps is the number of times the precision is doubled, if the
+// roots could not be certified.
+const maxPrecisionSteps = 6
+
+// certifiedRoots approximates the roots of the square free polynomial with
+// the real coefficients a and returns the inclusion radii. The imaginary
+// parts of roots which are certified to be real are set to zero. An error
+// is returned if the inclusion disks are not disjoint.
+func certifiedRoots(a []*big.Float, prec uint) (roots []bigComplex, radii []*big.Float, isReal []bool, err error) {
+	n := len(a) - 1
+	roots = aberth(prec, a)
+	radii = make([]*big.Float, n)
+	lc := new(big.Float).Abs(a[n])
+	for k, z := range roots {
+		p, _ := complexHorner(prec, a, z)
+		r := p.abs(prec)
+		r.Add(r, roundingError(prec, a, z.abs(prec)))
+		r.Mul(r, big.NewFloat(float64(n)))
+		d := new(big.Float).SetPrec(prec).Set(lc)
+		for j, w := range roots {
+			if j != k {
+				d.Mul(d, z.sub(prec, w).abs(prec))
+			}
+		}
+		radii[k] = r.Quo(r, d)
+	}
+	for k := range roots {
+		for j := k + 1; j < n; j++ {
+			r := new(big.Float).Add(radii[k], radii[j])
+			if roots[k].sub(prec, roots[j]).abs(prec).Cmp(r) <= 0 {
+				return nil, nil, nil, errors.New("the roots could not be separated")
+			}
+		}
+	}
+	isReal = make([]bool, n)
+	for k, z := range roots {
+		if new(big.Float).Abs(z.im).Cmp(radii[k]) > 0 {
+			continue
+		}
+		x := bigComplex{z.re, new(big.Float)}
+		r := new(big.Float).Add(radii[k], radii[k])
+		isReal[k] = true
+		for j, w := range roots {
+			if j != k && x.sub(prec, w).abs(prec).Cmp(new(big.Float).Add(r, radii[j])) <= 0 {
+				isReal[k] = false
+				break
+			}
+		}
+		if isReal[k] {
+			roots[k] = bigComplex{z.re, new(big.Float).SetPrec(prec)}
+		}
+	}
+	return roots, radii, isReal, nil
+}
@@ -14,34 +14,62 @@ import (
 type Polynomial struct {
 	vars  []string
 	order TermOrder
+	field Field
 	items []Monomial
 }
 
-func NewPolynomial(expr Expr) (*Polynomial, error) {
+func NewPolynomial(expr Expr, def Field, mode ExponentMode) (*Polynomial, error) {
 	if p, ok := expr.(*Polynomial); ok {
 		return p, nil
 	}
-	p := &Polynomial{order: LexTermOrder}
+	field, err := collectField(expr, def)
+	if err != nil {
+		return nil, err
+	}
+	p := &Polynomial{order: LexTermOrder, field: field}
 	p.vars = collectVars(expr)
-	if err := p.convert(expr); err != nil {
+	if err := p.convert(expr, mode); err != nil {
 		return nil, err
 	}
 	p.normalize()
 	return p, nil
 }
 
-func (p *Polynomial) convert(expr Expr) error {
+func (p *Polynomial) convert(expr Expr, mode ExponentMode) error {
 	if add, ok := expr.(Add); ok {
-		if err := p.convert(add.A); err != nil {
+		if err := p.convert(add.A, mode); err != nil {
+			return err
+		}
+		if err := p.convert(add.B, mode); err != nil {
+			return err
+		}
+		return nil
+	}
+	if sub, ok := expr.(Sub); ok {
+		if err := p.convert(sub.A, mode); err != nil {
 			return err
 		}
-		if err := p.convert(add.B); err != nil {
+		q := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+		if err := q.convert(sub.B, mode); err != nil {
 			return err
 		}
+		for _, m := range q.items {
+			p.items = append(p.items, Monomial{p.field.Neg(m.C), m.T})
+		}
+		p.normalize()
 		return nil
 	}
-	m := Monomial{*big.NewRat(1, 1), make([]big.Rat, len(p.vars))}
+	m := Monomial{p.field.One(), NewTerm(len(p.vars))}
 	if err := p.convertMonomial(expr, &m); err != nil {
+		switch x := expr.(type) {
+		case Mul:
+			return p.convertProduct(x.A, x.B, false, mode)
+		case Div:
+			return p.convertProduct(x.A, x.B, true, mode)
+		}
+		return err
+	}
+	if err := checkTerm(m.T, p.vars, mode); err != nil {
 		return err
 	}
 	p.items = append(p.items, m)
@@ -49,10 +77,53 @@ func (p *Polynomial) convert(expr Expr) error {
 	return nil
 }
 
+// convertProduct adds the product a*b, or the quotient a/b if div is true,
+// to p. Polynomials can only be divided by non-zero monomials.
+func (p *Polynomial) convertProduct(a, b Expr, div bool, mode ExponentMode) error {
+	q := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	if err := q.convert(a, mode); err != nil {
+		return err
+	}
+	r := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	if err := r.convert(b, mode); err != nil {
+		return err
+	}
+	if div {
+		if r.IsZero() {
+			return errors.New("division by zero")
+		}
+		if len(r.items) != 1 {
+			return fmt.Errorf("invalid division by %v, only monomials are allowed", r)
+		}
+		m := r.items[0]
+		q = q.MulMonomial(p.field.Quo(p.field.One(), m.C), termDiv(NewTerm(len(p.vars)), m.T))
+	} else {
+		q = q.Mul(r)
+	}
+	for _, m := range q.items {
+		if err := checkTerm(m.T, p.vars, mode); err != nil {
+			return err
+		}
+	}
+	p.items = append(p.items, q.items...)
+	p.normalize()
+	return nil
+}
+
 func (p *Polynomial) convertMonomial(expr Expr, m *Monomial) error {
 	switch x := expr.(type) {
 	case Num:
-		m.C.Mul(&m.C, x.Rat)
+		c, err := p.field.FromRat(x.Rat)
+		if err != nil {
+			return err
+		}
+		m.C = p.field.Mul(m.C, c)
+		return nil
+	case Elem:
+		if x.F != p.field {
+			return fmt.Errorf("incompatible fields %v and %v", x.F, p.field)
+		}
+		m.C = p.field.Mul(m.C, x.C)
 		return nil
 	case Mul:
 		if err := p.convertMonomial(x.A, m); err != nil {
@@ -62,6 +133,20 @@ func (p *Polynomial) convertMonomial(expr Expr, m *Monomial) error {
 			return err
 		}
 		return nil
+	case Div:
+		if err := p.convertMonomial(x.A, m); err != nil {
+			return err
+		}
+		d := Monomial{p.field.One(), NewTerm(len(p.vars))}
+		if err := p.convertMonomial(x.B, &d); err != nil {
+			return err
+		}
+		if p.field.IsZero(d.C) {
+			return errors.New("division by zero")
+		}
+		m.C = p.field.Quo(m.C, d.C)
+		m.T = termDiv(m.T, d.T)
+		return nil
 	case Pow:
 		ident, ok1 := x.A.(Ident)
 		exp, ok2 := x.B.(Num)
@@ -73,7 +158,7 @@ func (p *Polynomial) convertMonomial(expr Expr, m *Monomial) error {
 			}
 		}
 		if ok1 && ok2 && idx >= 0 {
-			m.T[idx].Add(&m.T[idx], exp.Rat)
+			m.T = m.T.addExp(idx, exp.Rat)
 			return nil
 		}
 	case Ident:
@@ -85,7 +170,7 @@ func (p *Polynomial) convertMonomial(expr Expr, m *Monomial) error {
 			}
 		}
 		if idx >= 0 {
-			m.T[idx].Add(&m.T[idx], ratOne)
+			m.T = m.T.addExp(idx, ratOne)
 			return nil
 		}
 	}
@@ -101,14 +186,14 @@ func (p *Polynomial) String() string {
 		if i > 0 {
 			buf.WriteString(" + ")
 		}
-		buf.WriteString(t.C.RatString())
+		buf.WriteString(p.field.Format(t.C))
 		for j := range p.vars {
-			if t.T[j].Sign() != 0 {
+			if t.T.Sign(j) != 0 {
 				buf.WriteByte('*')
 				buf.WriteString(p.vars[j])
-				if t.T[j].Cmp(ratOne) != 0 {
+				if e := t.T.Exp(j); e.Cmp(ratOne) != 0 {
 					buf.WriteByte('^')
-					buf.WriteString(t.T[j].RatString())
+					buf.WriteString(e.RatString())
 				}
 			}
 		}
@@ -120,25 +205,25 @@ func (p *Polynomial) String() string {
 }
 
 func (p *Polynomial) MultiCoeff(vars []string, exp []Num) *Polynomial {
-	rval := &Polynomial{vars: p.vars, order: p.order}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
 	idx := rval.indexVars(vars)
 	for _, term := range p.items {
 		valid := true
 		for i := range idx {
-			if idx[i] < 0 || term.T[idx[i]].Cmp(exp[i].Rat) != 0 {
+			if idx[i] < 0 || term.T.Exp(idx[i]).Cmp(exp[i].Rat) != 0 {
 				valid = false
 				break
 			}
 		}
 		if valid {
-			m := Monomial{term.C, make(Term, len(rval.vars))}
-			for i := range m.T {
-				m.T[i].Set(&term.T[i])
+			keep := make([]int, len(rval.vars))
+			for i := range keep {
+				keep[i] = i
 			}
 			for i := range idx {
-				m.T[idx[i]].SetInt64(0)
+				keep[idx[i]] = -1
 			}
-			rval.items = append(rval.items, m)
+			rval.items = append(rval.items, Monomial{term.C, term.T.remap(keep)})
 		}
 	}
 	return rval
@@ -168,7 +253,7 @@ func (p *Polynomial) Support(vars []string) [][]Num {
 		for j := 0; j < len(s[i]); j++ {
 			s[i][j].Rat = new(big.Rat)
 			if idx[j] >= 0 {
-				s[i][j].Rat.Set(&p.items[i].T[idx[j]])
+				s[i][j].Rat.Set(p.items[i].T.Exp(idx[j]))
 			} else {
 				s[i][j].Rat.SetInt64(0)
 			}
@@ -178,22 +263,22 @@ func (p *Polynomial) Support(vars []string) [][]Num {
 }
 
 func (p *Polynomial) LPP() *Polynomial {
-	rval := &Polynomial{vars: p.vars, order: p.order}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
 	if len(p.items) > 0 {
-		rval.items = append(rval.items, Monomial{*ratOne, p.items[0].T})
+		rval.items = append(rval.items, Monomial{p.field.One(), p.items[0].T})
 	}
 	return rval
 }
 
-func (p *Polynomial) LC() Num {
+func (p *Polynomial) LC() Expr {
 	if len(p.items) > 0 {
-		return Num{&p.items[0].C}
+		return coeffExpr(p.field, p.items[0].C)
 	}
-	return Num{big.NewRat(0, 1)}
+	return coeffExpr(p.field, p.field.Zero())
 }
 
 func (p *Polynomial) LM() *Polynomial {
-	rval := &Polynomial{vars: p.vars, order: p.order}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
 	if len(p.items) > 0 {
 		rval.items = p.items[:1]
 	}
@@ -204,14 +289,14 @@ func (p *Polynomial) Higher(t Term) *Polynomial {
 	n := sort.Search(len(p.items), func(i int) bool {
 		return !p.order(t, p.items[i].T)
 	})
-	return &Polynomial{vars: p.vars, order: p.order, items: p.items[:n]}
+	return &Polynomial{vars: p.vars, order: p.order, field: p.field, items: p.items[:n]}
 }
 
 func (p *Polynomial) Lower(t Term) *Polynomial {
 	n := sort.Search(len(p.items), func(i int) bool {
 		return p.order(p.items[i].T, t)
 	})
-	return &Polynomial{vars: p.vars, order: p.order, items: p.items[n:]}
+	return &Polynomial{vars: p.vars, order: p.order, field: p.field, items: p.items[n:]}
 }
 
 func (p *Polynomial) Between(t1, t2 Term) *Polynomial {
@@ -219,15 +304,15 @@ func (p *Polynomial) Between(t1, t2 Term) *Polynomial {
 }
 
 func (p *Polynomial) Remainder() *Polynomial {
-	rval := &Polynomial{vars: p.vars, order: p.order}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
 	if len(p.items) > 0 {
 		rval.items = p.items[1:]
 	}
 	return rval
 }
 
-func (p *Polynomial) ReduceTerm(f *Polynomial, t Term) (*Polynomial, error) {
-	if len(p.vars) != len(t) {
+func (p *Polynomial) ReduceTerm(f *Polynomial, t Term, mode ExponentMode) (*Polynomial, error) {
+	if len(p.vars) != t.Len() {
 		return nil, fmt.Errorf("invalid term")
 	}
 	if len(f.items) == 0 {
@@ -237,48 +322,29 @@ func (p *Polynomial) ReduceTerm(f *Polynomial, t Term) (*Polynomial, error) {
 	if idx < 0 {
 		return nil, fmt.Errorf("invalid term (not in support)")
 	}
-	u := Monomial{T: make(Term, len(p.vars))}
-	u.C.Quo(&p.items[idx].C, &f.items[0].C)
-	u.C.Neg(&u.C)
-	for j := 0; j < len(p.vars); j++ {
-		u.T[j].Sub(&p.items[idx].T[j], &f.items[0].T[j])
-	}
-	h := &Polynomial{vars: p.vars, order: p.order}
-	for i := 0; i < len(f.items); i++ {
-		m := Monomial{T: make(Term, len(p.vars))}
-		m.C.Mul(&u.C, &f.items[i].C)
-		for j := 0; j < len(p.vars); j++ {
-			m.T[j].Add(&u.T[j], &f.items[i].T[j])
-		}
-		h.items = append(h.items, m)
-	}
-	for i := 0; i < len(p.items); i++ {
-		pos := h.findTerm(p.items[i].T)
-		if pos < 0 {
-			h.items = append(h.items, p.items[i])
-		} else {
-			h.items[pos].C.Add(&h.items[pos].C, &p.items[i].C)
-		}
-	}
-	if !h.valid() {
+	u := Monomial{T: termDiv(p.items[idx].T, f.items[0].T)}
+	u.C = p.field.Neg(p.field.Quo(p.items[idx].C, f.items[0].C))
+	// multiplying with a term preserves the order of the items, therefore
+	// both polynomials can simply be merged
+	h := p.Add(f.MulMonomial(u.C, u.T))
+	if !h.valid(mode) {
 		return nil, fmt.Errorf("invalid reduction %v", h)
 	}
-	h.normalize()
 	return h, nil
 }
 
-func (p *Polynomial) Reduce(f *Polynomial) *Polynomial {
+func (p *Polynomial) Reduce(f *Polynomial, mode ExponentMode) *Polynomial {
 	for i := 0; i < len(p.items); i++ {
-		if h, err := p.ReduceTerm(f, p.items[i].T); err == nil {
+		if h, err := p.ReduceTerm(f, p.items[i].T, mode); err == nil {
 			return h
 		}
 	}
 	return p
 }
 
-func (p *Polynomial) ReduceAny(fns []*Polynomial) *Polynomial {
+func (p *Polynomial) ReduceAny(fns []*Polynomial, mode ExponentMode) *Polynomial {
 	for i := range fns {
-		h := p.Reduce(fns[i])
+		h := p.Reduce(fns[i], mode)
 		if !p.Equal(h) {
 			fmt.Printf("reduced by %v to %v\n", fns[i], h)
 			return h
@@ -291,40 +357,315 @@ func (p *Polynomial) Equal(q *Polynomial) bool {
 	if p == q {
 		return true
 	}
-	if len(p.vars) != len(q.vars) || len(p.items) != len(q.items) {
+	if len(p.vars) != len(q.vars) || len(p.items) != len(q.items) ||
+		p.field != q.field {
 		return false
 	}
 	for i := 0; i < len(p.items); i++ {
-		if p.items[i].C.Cmp(&q.items[i].C) != 0 {
+		if !p.field.Equal(p.items[i].C, q.items[i].C) ||
+			!termEqual(p.items[i].T, q.items[i].T) {
 			return false
 		}
-		for j := 0; j < len(p.vars); j++ {
-			if p.items[i].T[j].Cmp(&q.items[i].T[j]) != 0 {
-				return false
+	}
+	return true
+}
+
+func (p *Polynomial) IsZero() bool {
+	return len(p.items) == 0
+}
+
+func (p *Polynomial) Add(q *Polynomial) *Polynomial {
+	return p.combine(q, p.field.One())
+}
+
+func (p *Polynomial) Sub(q *Polynomial) *Polynomial {
+	return p.combine(q, p.field.Neg(p.field.One()))
+}
+
+// combine calculates p + c*q by merging the sorted items of both polynomials.
+func (p *Polynomial) combine(q *Polynomial, c Coeff) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	rval.items = make([]Monomial, 0, len(p.items)+len(q.items))
+	i, j := 0, 0
+	for i < len(p.items) && j < len(q.items) {
+		a, b := p.items[i], q.items[j]
+		switch {
+		case p.order(b.T, a.T):
+			rval.items = append(rval.items, a)
+			i++
+		case p.order(a.T, b.T):
+			rval.items = append(rval.items, Monomial{p.field.Mul(c, b.C), b.T})
+			j++
+		default:
+			x := p.field.Add(a.C, p.field.Mul(c, b.C))
+			if !p.field.IsZero(x) {
+				rval.items = append(rval.items, Monomial{x, a.T})
 			}
+			i++
+			j++
 		}
 	}
-	return true
+	rval.items = append(rval.items, p.items[i:]...)
+	for ; j < len(q.items); j++ {
+		b := q.items[j]
+		rval.items = append(rval.items, Monomial{p.field.Mul(c, b.C), b.T})
+	}
+	return rval
 }
 
-func (p *Polynomial) normalize() {
-	for i := 0; i < len(p.items); i++ {
-		if p.items[i].C.Sign() == 0 {
-			n := len(p.items) - 1
-			p.items[i], p.items[n] = p.items[n], p.items[i]
-			p.items = p.items[:n]
-			i--
+// MulMonomial multiplies every item of p with the monomial c*t.
+func (p *Polynomial) MulMonomial(c Coeff, t Term) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	if p.field.IsZero(c) {
+		return rval
+	}
+	rval.items = make([]Monomial, len(p.items))
+	for i := range p.items {
+		rval.items[i].C = p.field.Mul(c, p.items[i].C)
+		rval.items[i].T = termMul(t, p.items[i].T)
+	}
+	return rval
+}
+
+func (p *Polynomial) Mul(q *Polynomial) *Polynomial {
+	if rval, ok := p.mulDense(q); ok {
+		return rval
+	}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	for i := range q.items {
+		rval = rval.Add(p.MulMonomial(q.items[i].C, q.items[i].T))
+	}
+	return rval
+}
+
+// Monic divides p by its leading coefficient.
+func (p *Polynomial) Monic() *Polynomial {
+	if len(p.items) == 0 {
+		return p
+	}
+	return p.MulMonomial(p.field.Quo(p.field.One(), p.items[0].C),
+		NewTerm(len(p.vars)))
+}
+
+// NormalForm fully reduces p modulo the polynomials in fns. The leading
+// term of the remainder is cancelled repeatedly until no leading term of
+// fns divides it anymore. The intermediate results are accumulated in a
+// geobucket.
+func (p *Polynomial) NormalForm(fns []*Polynomial) *Polynomial {
+	return p.normalForm(fns, nil)
+}
+
+// normalForm is NormalForm, which calls trace, if it is not nil, with the
+// reductor and the intermediate result after every reduction step.
+func (p *Polynomial) normalForm(fns []*Polynomial, trace func(f, h *Polynomial)) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	h := newGeobucket(p)
+	for {
+		lt, ok := h.PopLead()
+		if !ok {
+			break
+		}
+		var f *Polynomial
+		for i := range fns {
+			if len(fns[i].items) > 0 && termDivides(fns[i].items[0].T, lt.T) {
+				f = fns[i]
+				break
+			}
+		}
+		if f == nil {
+			rval.items = append(rval.items, lt)
+			continue
+		}
+		c := p.field.Neg(p.field.Quo(lt.C, f.items[0].C))
+		h.Add(f.Remainder().MulMonomial(c, termDiv(lt.T, f.items[0].T)))
+		if trace != nil {
+			trace(f, rval.Add(h.Polynomial()))
+		}
+	}
+	return rval
+}
+
+// divide divides p by the polynomials in fns and returns the quotients
+// q_i and the remainder r with p = sum q_i*fns[i] + r, where no term of r
+// is divisible by a leading term of fns.
+func (p *Polynomial) divide(fns []*Polynomial) ([]*Polynomial, *Polynomial) {
+	quo := make([]*Polynomial, len(fns))
+	for i := range quo {
+		quo[i] = &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	h := p
+	for !h.IsZero() {
+		lt := h.items[0]
+		found := false
+		for i, f := range fns {
+			if len(f.items) > 0 && termDivides(f.items[0].T, lt.T) {
+				c := p.field.Quo(lt.C, f.items[0].C)
+				t := termDiv(lt.T, f.items[0].T)
+				quo[i].items = append(quo[i].items, Monomial{c, t})
+				h = h.Sub(f.MulMonomial(c, t))
+				found = true
+				break
+			}
+		}
+		if !found {
+			rval.items = append(rval.items, lt)
+			h = h.Remainder()
+		}
+	}
+	return quo, rval
+}
+
+// SPolynomial calculates the S-polynomial of f and g, a combination of both
+// polynomials in which the leading terms cancel each other.
+func SPolynomial(f, g *Polynomial) *Polynomial {
+	lcm := termLCM(f.items[0].T, g.items[0].T)
+	a := f.MulMonomial(f.field.Quo(f.field.One(), f.items[0].C),
+		termDiv(lcm, f.items[0].T))
+	b := g.MulMonomial(g.field.Quo(g.field.One(), g.items[0].C),
+		termDiv(lcm, g.items[0].T))
+	return a.Sub(b)
+}
+
+// degreeIn returns the degree of p in the i-th variable, or -1 if p is
+// zero. The second return value is false if an exponent of the variable is
+// not a non-negative integer.
+func (p *Polynomial) degreeIn(i int) (int, bool) {
+	deg := -1
+	for _, m := range p.items {
+		e, ok := m.T.Int(i)
+		if !ok || e < 0 {
+			return 0, false
+		}
+		if e > deg {
+			deg = e
+		}
+	}
+	return deg, true
+}
+
+// coeffIn returns the coefficient of x_i^d of p considered as a univariate
+// polynomial in the i-th variable.
+func (p *Polynomial) coeffIn(i, d int) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	idx := make([]int, len(p.vars))
+	for j := range idx {
+		idx[j] = j
+	}
+	idx[i] = -1
+	for _, m := range p.items {
+		if e, _ := m.T.Int(i); e == d {
+			rval.items = append(rval.items, Monomial{m.C, m.T.remap(idx)})
+		}
+	}
+	rval.normalize()
+	return rval
+}
+
+// PseudoDivide calculates the pseudo-quotient q and pseudo-remainder r of p
+// and g with respect to the i-th variable, which satisfy
+// lc(g)^(deg(p)-deg(g)+1) * p = q*g + r and deg(r) < deg(g), where the
+// degrees and the leading coefficient lc(g) are taken with respect to x_i.
+func (p *Polynomial) PseudoDivide(g *Polynomial, i int) (q, r *Polynomial, err error) {
+	dp, ok1 := p.degreeIn(i)
+	dg, ok2 := g.degreeIn(i)
+	if !ok1 || !ok2 {
+		return nil, nil, fmt.Errorf("pseudo-division by %s requires integer exponents", p.vars[i])
+	}
+	if g.IsZero() {
+		return nil, nil, errors.New("division by zero")
+	}
+	q = &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	r = p
+	lc := g.coeffIn(i, dg)
+	e := dp - dg + 1
+	for d := dp; !r.IsZero() && d >= dg; d, _ = r.degreeIn(i) {
+		t := r.coeffIn(i, d).MulMonomial(p.field.One(),
+			NewTerm(len(p.vars)).addExp(i, big.NewRat(int64(d-dg), 1)))
+		q = lc.Mul(q).Add(t)
+		r = lc.Mul(r).Sub(t.Mul(g))
+		e--
+	}
+	for ; e > 0; e-- {
+		q, r = lc.Mul(q), lc.Mul(r)
+	}
+	return q, r, nil
+}
+
+// mapCoeffs converts the coefficients of p into the field f using the
+// function fn.
+func (p *Polynomial) mapCoeffs(f Field, fn func(c Coeff) (Coeff, error)) (*Polynomial, error) {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: f}
+	rval.items = make([]Monomial, 0, len(p.items))
+	for i := range p.items {
+		c, err := fn(p.items[i].C)
+		if err != nil {
+			return nil, err
+		}
+		if !f.IsZero(c) {
+			rval.items = append(rval.items, Monomial{c, p.items[i].T})
+		}
+	}
+	return rval, nil
+}
+
+// withOrder returns a copy of p whose terms are sorted by order.
+func (p *Polynomial) withOrder(order TermOrder) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: order, field: p.field}
+	rval.items = make([]Monomial, len(p.items))
+	copy(rval.items, p.items)
+	SortMonomial(rval.items, order)
+	return rval
+}
+
+// embed rewrites p as a polynomial in the variables vars, which must be a
+// superset of the variables of p.
+func (p *Polynomial) embed(vars []string, order TermOrder) (*Polynomial, error) {
+	idx := p.indexVars(vars)
+	used := 0
+	for i := range idx {
+		if idx[i] >= 0 {
+			used++
 		}
 	}
+	if used != len(p.vars) {
+		return nil, fmt.Errorf("invalid variables %v", vars)
+	}
+	rval := &Polynomial{vars: vars, order: order, field: p.field}
+	rval.items = make([]Monomial, len(p.items))
+	for i := range p.items {
+		rval.items[i] = Monomial{p.items[i].C, p.items[i].T.remap(idx)}
+	}
+	rval.normalize()
+	return rval, nil
+}
+
+// normalize sorts the items of p, combines items with equal terms and
+// removes items with a zero coefficient.
+func (p *Polynomial) normalize() {
 	SortMonomial(p.items, p.order)
+	items := make([]Monomial, 0, len(p.items))
+	for _, m := range p.items {
+		if n := len(items); n > 0 && termEqual(items[n-1].T, m.T) {
+			items[n-1].C = p.field.Add(items[n-1].C, m.C)
+		} else {
+			items = append(items, m)
+		}
+	}
+	n := 0
+	for i := range items {
+		if !p.field.IsZero(items[i].C) {
+			items[n] = items[i]
+			n++
+		}
+	}
+	p.items = items[:n]
 }
 
-func (p *Polynomial) valid() bool {
+func (p *Polynomial) valid(mode ExponentMode) bool {
 	for i := 0; i < len(p.items); i++ {
-		for j := 0; j < len(p.vars); j++ {
-			if p.items[i].T[j].Sign() < 0 {
-				return false
-			}
+		if checkTerm(p.items[i].T, p.vars, mode) != nil {
+			return false
 		}
 	}
 	return true
@@ -337,29 +678,33 @@ func (p *Polynomial) findTerm(t Term) int {
 	if i < 0 || i >= len(p.items) {
 		return -1
 	}
-	for j := 0; j < len(p.vars); j++ {
-		if p.items[i].T[j].Cmp(&t[j]) != 0 {
-			return -1
-		}
+	if !termEqual(p.items[i].T, t) {
+		return -1
 	}
 	return i
 }
 
 type Monomial struct {
-	C big.Rat
+	C Coeff
 	T Term
 }
 
-type Term []big.Rat
-
 type TermOrder func(a, b Term) bool
 
 func LexTermOrder(a, b Term) bool {
-	if len(a) != len(b) {
+	if a.Len() != b.Len() {
+		return false
+	}
+	if a.compact() && b.compact() {
+		for i := range a.exp {
+			if a.exp[i] != b.exp[i] {
+				return a.exp[i] < b.exp[i]
+			}
+		}
 		return false
 	}
-	for i := 0; i < len(a); i++ {
-		x := a[i].Cmp(&b[i])
+	for i := 0; i < a.Len(); i++ {
+		x := termCmp(a, b, i)
 		if x < 0 {
 			return true
 		} else if x > 0 {
@@ -370,11 +715,11 @@ func LexTermOrder(a, b Term) bool {
 }
 
 func LexTermOrderRev(a, b Term) bool {
-	if len(a) != len(b) {
+	if a.Len() != b.Len() {
 		return false
 	}
-	for i := len(a) - 1; i >= 0; i-- {
-		x := a[i].Cmp(&b[i])
+	for i := a.Len() - 1; i >= 0; i-- {
+		x := termCmp(a, b, i)
 		if x < 0 {
 			return true
 		} else if x > 0 {
@@ -385,13 +730,13 @@ func LexTermOrderRev(a, b Term) bool {
 }
 
 func TotalTermOrder(a, b Term) bool {
-	sumA := big.NewRat(0, 1)
-	sumB := big.NewRat(0, 1)
-	for i := 0; i < len(a); i++ {
-		sumA.Add(sumA, &a[i])
-		sumB.Add(sumB, &b[i])
+	if a.compact() && b.compact() {
+		if a.deg != b.deg {
+			return a.deg < b.deg
+		}
+		return LexTermOrder(a, b)
 	}
-	x := sumA.Cmp(sumB)
+	x := a.Degree().Cmp(b.Degree())
 	if x < 0 {
 		return true
 	} else if x > 0 {
@@ -400,6 +745,47 @@ func TotalTermOrder(a, b Term) bool {
 	return LexTermOrder(a, b)
 }
 
+// GrevlexTermOrder is the graded reverse lexicographic order. Terms are
+// compared by their total degree first, ties are broken by the last
+// variable with different exponents: the term with the smaller exponent
+// is the larger one.
+func GrevlexTermOrder(a, b Term) bool {
+	if a.compact() && b.compact() {
+		if a.deg != b.deg {
+			return a.deg < b.deg
+		}
+		for i := len(a.exp) - 1; i >= 0; i-- {
+			if a.exp[i] != b.exp[i] {
+				return a.exp[i] > b.exp[i]
+			}
+		}
+		return false
+	}
+	x := a.Degree().Cmp(b.Degree())
+	if x < 0 {
+		return true
+	} else if x > 0 {
+		return false
+	}
+	return LexTermOrderRev(b, a)
+}
+
+// termOrders maps the names of the term orders to their implementation.
+var termOrders = map[string]TermOrder{
+	"lex":     LexTermOrder,
+	"lexrev":  LexTermOrderRev,
+	"total":   TotalTermOrder,
+	"grevlex": GrevlexTermOrder,
+}
+
+func termOrderByName(name string) (TermOrder, error) {
+	order, ok := termOrders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown term order %q", name)
+	}
+	return order, nil
+}
+
 type monomialSorter struct {
 	items []Monomial
 	order TermOrder
@@ -0,0 +1,557 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// PrimaryDecomposition decomposes an ideal over the rationals into primary
+// ideals and returns the primary components together with their associated
+// primes. Zero-dimensional ideals are decomposed directly, also over number
+// fields, the other ones are reduced to zero-dimensional ideals over a field
+// of rational functions by primdecGTZ.
+func PrimaryDecomposition(I *Ideal) (primary, primes []*Ideal, err error) {
+	if ext, ok := I.field.(*ExtensionField); I.field != Rationals && (!ok || ext.base != Rationals) {
+		return nil, nil, errors.New("primary decomposition requires rational coefficients")
+	}
+	basis := I.Basis()
+	if len(basis) == 0 {
+		// the zero ideal is prime
+		return []*Ideal{I}, []*Ideal{I}, nil
+	}
+	if basis[0].items[0].T.Degree().Sign() == 0 {
+		return nil, nil, nil
+	}
+	if !isZeroDimensional(basis) {
+		if I.field != Rationals {
+			return nil, nil, errors.New("primary decomposition of positive-dimensional ideals requires rational coefficients")
+		}
+		primary, primes, err = primdecGTZ(I, basis)
+		if err != nil {
+			return nil, nil, err
+		}
+		primary, primes, err = irredundant(primary, primes)
+		return primary, primes, err
+	}
+	return primdecZeroDim(I, basis)
+}
+
+// primdecZeroDim decomposes the zero-dimensional ideal I with the reduced
+// Gröbner basis basis. Following Gianni, Trager and Zacharias, a linear
+// form l is searched which takes distinct values at all solutions, i.e.
+// whose minimal polynomial m modulo I has as many distinct roots as the
+// radical of I has solutions. The factorization m = q_1^e_1*...*q_r^e_r
+// over the field of I then yields the components I + <q_j(l)^e_j>.
+func primdecZeroDim(I *Ideal, basis []*Polynomial) (primary, primes []*Ideal, err error) {
+	rad, err := Radical(I)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newQuotientRing(rad)
+	if err != nil {
+		return nil, nil, err
+	}
+	points := len(q.terms)
+
+	var l *Polynomial
+	var m, s []Coeff
+	for c := int64(0); ; c++ {
+		l = separatingForm(I, len(I.vars), c)
+		m = minimalPolynomialOf(basis, l)
+		s = upolySquarefree(I.field, m)
+		if upolyDegree(s) == points {
+			break
+		}
+	}
+	_, factors, _, err := upolyFactor(I.field, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(factors) == 1 {
+		return []*Ideal{NewIdeal(basis)}, []*Ideal{rad}, nil
+	}
+	for _, f := range factors {
+		// the multiplicity of the factor f in m
+		pow := []Coeff{I.field.One()}
+		for rest := m; ; {
+			quo, r := upolyDivMod(I.field, rest, f)
+			if len(r) > 0 {
+				break
+			}
+			pow, rest = upolyMul(I.field, pow, f), quo
+		}
+		fns := append([]*Polynomial{upolyCompose(pow, l)}, basis...)
+		J := NewIdeal(Groebner(fns))
+		P, err := Radical(J)
+		if err != nil {
+			return nil, nil, err
+		}
+		primary, primes = append(primary, J), append(primes, P)
+	}
+	return primary, primes, nil
+}
+
+// separatingForm returns the c-th candidate for a linear form in the first
+// n variables of I separating the solutions of I. The variables are tried
+// first, starting with the smallest one, followed by x_1 + k*x_2 + ... +
+// k^(n-1)*x_n for k = 1, 2, ...
+func separatingForm(I *Ideal, n int, c int64) *Polynomial {
+	l := &Polynomial{vars: I.vars, order: I.order, field: I.field}
+	if c < int64(n) {
+		t := NewTerm(len(I.vars)).addExp(n-1-int(c), big.NewRat(1, 1))
+		l.items = []Monomial{{I.field.One(), t}}
+		return l
+	}
+	k, x := big.NewRat(c-int64(n)+1, 1), big.NewRat(1, 1)
+	for i := 0; i < n; i++ {
+		t := NewTerm(len(I.vars)).addExp(i, big.NewRat(1, 1))
+		c, _ := I.field.FromRat(x)
+		l = l.Add(&Polynomial{vars: I.vars, order: I.order, field: I.field,
+			items: []Monomial{{c, t}}})
+		x.Mul(x, k)
+	}
+	return l
+}
+
+// upolyCompose evaluates the univariate polynomial a at the polynomial g
+// using Horner's scheme.
+func upolyCompose(a []Coeff, g *Polynomial) *Polynomial {
+	f := g.field
+	one := NewTerm(len(g.vars))
+	rval := &Polynomial{vars: g.vars, order: g.order, field: f}
+	for i := len(a) - 1; i >= 0; i-- {
+		rval = rval.Mul(g)
+		if !f.IsZero(a[i]) {
+			rval = rval.Add(&Polynomial{vars: g.vars, order: g.order, field: f,
+				items: []Monomial{{a[i], one}}})
+		}
+	}
+	return rval
+}
+
+// localization describes the polynomial ring Q[x, u] with the lex order, in
+// which the variables x are bigger than the independent variables u. If u
+// is a maximal independent set of an ideal I, the extension of I to K[x]
+// over the field K = Q(u) of rational functions is zero-dimensional, and a
+// Gröbner basis of I in this ring is a Gröbner basis of the extension.
+type localization struct {
+	vars []string
+	nx   int
+}
+
+func (r *localization) variable(i int) *Polynomial {
+	return &Polynomial{vars: r.vars, order: LexTermOrder, field: Rationals,
+		items: []Monomial{{big.NewRat(1, 1), NewTerm(len(r.vars)).addExp(i, ratOne)}}}
+}
+
+func (r *localization) one() *Polynomial {
+	return &Polynomial{vars: r.vars, order: LexTermOrder, field: Rationals,
+		items: []Monomial{{big.NewRat(1, 1), NewTerm(len(r.vars))}}}
+}
+
+// leadingCoefficient returns the leading coefficient of f in K[x], which is
+// a polynomial in u.
+func (r *localization) leadingCoefficient(f *Polynomial) *Polynomial {
+	idx := make([]int, len(r.vars))
+	for i := range idx {
+		idx[i] = i
+		if i < r.nx {
+			idx[i] = -1
+		}
+	}
+	lc := &Polynomial{vars: r.vars, order: LexTermOrder, field: Rationals}
+	for _, m := range f.items {
+		same := true
+		for i := 0; i < r.nx && same; i++ {
+			same = m.T.Exp(i).Cmp(f.items[0].T.Exp(i)) == 0
+		}
+		if same {
+			lc.items = append(lc.items, Monomial{m.C, m.T.remap(idx)})
+		}
+	}
+	lc.normalize()
+	return lc
+}
+
+// denominator returns the product of the leading coefficients of the
+// Gröbner basis in K[x]. The polynomials of Q[x, u], which are contained in
+// the extension of the ideal, are the ones of the saturation by it.
+func (r *localization) denominator(basis []*Polynomial) *Polynomial {
+	h := r.one()
+	for _, g := range basis {
+		h = h.Mul(r.leadingCoefficient(g))
+	}
+	return h
+}
+
+// contract calculates the contraction of the extension of the ideal
+// generated by fns to K[x], i.e. its intersection with Q[x, u].
+func (r *localization) contract(fns []*Polynomial) (*Ideal, error) {
+	basis := Groebner(fns)
+	h := r.denominator(basis)
+	if isConstant(h) {
+		return NewIdeal(basis), nil
+	}
+	return Saturate(NewIdeal(basis), h)
+}
+
+// minimalPolynomial calculates the minimal polynomial of f modulo the
+// zero-dimensional ideal in K[x] with the Gröbner basis basis. It is
+// returned as a polynomial in t and u with integer coefficients, where t is
+// a new variable between x and u. The eliminant of smallest degree in t of
+// the ideal generated by basis and t - f is the minimal polynomial up to a
+// factor in K.
+func (r *localization) minimalPolynomial(basis []*Polynomial, f *Polynomial) *Polynomial {
+	vars := append(append(append([]string{}, r.vars[:r.nx]...), "@t"), r.vars[r.nx:]...)
+	t := &Polynomial{vars: vars, order: LexTermOrder, field: Rationals,
+		items: []Monomial{{big.NewRat(1, 1), NewTerm(len(vars)).addExp(r.nx, ratOne)}}}
+	g, _ := f.embed(vars, LexTermOrder)
+	fns := []*Polynomial{t.Sub(g)}
+	for _, b := range basis {
+		g, _ = b.embed(vars, LexTermOrder)
+		fns = append(fns, g)
+	}
+	var m *Polynomial
+	for _, g := range Groebner(fns) {
+		free := true
+		for i := 0; i < r.nx && free; i++ {
+			free = g.items[0].T.Sign(i) == 0
+		}
+		e, _ := g.items[0].T.Int(r.nx)
+		if !free || e == 0 {
+			continue
+		}
+		if m == nil {
+			m = g
+		} else if d, _ := m.items[0].T.Int(r.nx); e < d {
+			m = g
+		}
+	}
+	return m
+}
+
+// factorOver factors the polynomial m in t and u over K and returns the
+// irreducible factors, which contain t, with their multiplicities.
+func (r *localization) factorOver(m *Polynomial) (factors []*Polynomial, mult []int) {
+	fs, ms := polyFactorRationals(m)
+	for i, f := range fs {
+		if e, _ := f.items[0].T.Int(r.nx); e > 0 {
+			factors, mult = append(factors, f), append(mult, ms[i])
+		}
+	}
+	return factors, mult
+}
+
+// substitute replaces t by f in the polynomial q in t and u.
+func (r *localization) substitute(q, f *Polynomial) *Polynomial {
+	idx := make([]int, len(r.vars))
+	for i := range idx {
+		idx[i] = i
+		if i >= r.nx {
+			idx[i] = i + 1
+		}
+	}
+	deg, _ := q.items[0].T.Int(r.nx)
+	coeffs := make([]*Polynomial, deg+1)
+	for k := range coeffs {
+		coeffs[k] = &Polynomial{vars: r.vars, order: LexTermOrder, field: Rationals}
+	}
+	for _, m := range q.items {
+		k, _ := m.T.Int(r.nx)
+		coeffs[k].items = append(coeffs[k].items, Monomial{m.C, m.T.remap(idx)})
+	}
+	rval := &Polynomial{vars: r.vars, order: LexTermOrder, field: Rationals}
+	for k := deg; k >= 0; k-- {
+		coeffs[k].normalize()
+		rval = rval.Mul(f).Add(coeffs[k])
+	}
+	return rval
+}
+
+// points returns the dimension of K[x] modulo the zero-dimensional ideal
+// with the Gröbner basis basis, i.e. the number of standard monomials in x.
+func (r *localization) points(basis []*Polynomial) int {
+	lead := make([][]int, len(basis))
+	for k, g := range basis {
+		lead[k] = make([]int, r.nx)
+		for i := range lead[k] {
+			lead[k][i], _ = g.items[0].T.Int(i)
+		}
+	}
+	standard := func(e []int) bool {
+		for _, l := range lead {
+			divides := true
+			for i := range l {
+				divides = divides && l[i] <= e[i]
+			}
+			if divides {
+				return false
+			}
+		}
+		return true
+	}
+	queue := [][]int{make([]int, r.nx)}
+	seen := map[string]bool{fmt.Sprint(queue[0]): true}
+	for k := 0; k < len(queue); k++ {
+		for i := 0; i < r.nx; i++ {
+			e := append([]int{}, queue[k]...)
+			e[i]++
+			if key := fmt.Sprint(e); !seen[key] && standard(e) {
+				seen[key] = true
+				queue = append(queue, e)
+			}
+		}
+	}
+	return len(queue)
+}
+
+// primdecGTZ decomposes the positive-dimensional ideal I with the reduced
+// Gröbner basis basis following Gianni, Trager and Zacharias. For a maximal
+// independent set u, the extension of I to K[x] with K = Q(u) is
+// zero-dimensional and is decomposed like in primdecZeroDim, where the
+// minimal polynomials are factored over K, i.e. as polynomials in t and u.
+// The contractions of its components to Q[x, u] are the components of the
+// saturation I : h^∞, where h is the product of the leading coefficients in
+// K[x]. Since I is the intersection of I : h^s and I + <h^s> for large s,
+// the remaining components are those of the bigger ideal I + <h^s>.
+func primdecGTZ(I *Ideal, basis []*Polynomial) (primary, primes []*Ideal, err error) {
+	indep := independentSet(basis)
+	r := &localization{}
+	for i, v := range I.vars {
+		if !indep[i] {
+			r.vars = append(r.vars, v)
+		}
+	}
+	r.nx = len(r.vars)
+	for i, v := range I.vars {
+		if indep[i] {
+			r.vars = append(r.vars, v)
+		}
+	}
+	var G []*Polynomial
+	for _, f := range basis {
+		g, err := f.embed(r.vars, LexTermOrder)
+		if err != nil {
+			return nil, nil, err
+		}
+		G = append(G, g)
+	}
+	G = Groebner(G)
+
+	// the radical of the extension by Seidenberg's lemma
+	rad := append([]*Polynomial{}, G...)
+	for i := 0; i < r.nx; i++ {
+		x := r.variable(i)
+		factors, _ := r.factorOver(r.minimalPolynomial(G, x))
+		s := r.one()
+		for _, q := range factors {
+			s = s.Mul(r.substitute(q, x))
+		}
+		rad = append(rad, s)
+	}
+	rad = Groebner(rad)
+	points := r.points(rad)
+
+	ring := &Ideal{vars: r.vars, order: LexTermOrder, field: Rationals}
+	var l *Polynomial
+	var factors []*Polynomial
+	var mult []int
+	for c := int64(0); ; c++ {
+		l = separatingForm(ring, r.nx, c)
+		factors, mult = r.factorOver(r.minimalPolynomial(G, l))
+		deg := 0
+		for _, q := range factors {
+			e, _ := q.items[0].T.Int(r.nx)
+			deg += e
+		}
+		if deg == points {
+			break
+		}
+	}
+	for j, q := range factors {
+		f := r.substitute(q, l)
+		pow := r.one()
+		for k := 0; k < mult[j]; k++ {
+			pow = pow.Mul(f)
+		}
+		Q, err := r.contract(append([]*Polynomial{pow}, G...))
+		if err != nil {
+			return nil, nil, err
+		}
+		P, err := r.contract(append([]*Polynomial{f}, rad...))
+		if err != nil {
+			return nil, nil, err
+		}
+		if Q, err = inRing(Q, I); err != nil {
+			return nil, nil, err
+		}
+		if P, err = inRing(P, I); err != nil {
+			return nil, nil, err
+		}
+		primary, primes = append(primary, Q), append(primes, P)
+	}
+
+	h := r.denominator(G)
+	if isConstant(h) {
+		return primary, primes, nil
+	}
+	if h, err = h.embed(I.vars, I.order); err != nil {
+		return nil, nil, err
+	}
+	// the smallest s with I : h^s = I : h^(s+1)
+	H := NewIdeal([]*Polynomial{h})
+	J, pow := NewIdeal(basis), I.unit()
+	for {
+		K, err := Quotient(J, H)
+		if err != nil {
+			return nil, nil, err
+		}
+		if idealEqual(J, K) {
+			break
+		}
+		J, pow = K, pow.Mul(h)
+	}
+	if isConstant(pow) {
+		return primary, primes, nil
+	}
+	p2, q2, err := PrimaryDecomposition(NewIdeal(append([]*Polynomial{pow}, basis...)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(primary, p2...), append(primes, q2...), nil
+}
+
+// independentSet returns a maximal independent set of variables of the
+// ideal with the Gröbner basis basis, i.e. a largest set of variables, such
+// that no leading term is a product of these variables only.
+func independentSet(basis []*Polynomial) []bool {
+	n := len(basis[0].vars)
+	for size := n; size > 0; size-- {
+		for _, subset := range combinations(n, size) {
+			indep := make([]bool, n)
+			for _, i := range subset {
+				indep[i] = true
+			}
+			ok := true
+			for _, g := range basis {
+				only := true
+				for i := 0; i < n && only; i++ {
+					only = indep[i] || g.items[0].T.Sign(i) == 0
+				}
+				if only {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				return indep
+			}
+		}
+	}
+	return make([]bool, n)
+}
+
+// inRing rewrites the ideal J in the ring of I and returns its reduced
+// Gröbner basis.
+func inRing(J, I *Ideal) (*Ideal, error) {
+	var fns []*Polynomial
+	for _, f := range J.gens {
+		g, err := f.embed(I.vars, I.order)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, g)
+	}
+	return NewIdeal(Groebner(fns)), nil
+}
+
+// idealContains reports whether every generator of b is contained in a.
+func idealContains(a, b *Ideal) bool {
+	basis := a.Basis()
+	for _, f := range b.gens {
+		if !f.NormalForm(basis).IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func idealEqual(a, b *Ideal) bool {
+	return idealContains(a, b) && idealContains(b, a)
+}
+
+// irredundant combines the primary components with the same associated
+// prime by intersecting them and removes the components, which contain the
+// intersection of the other ones.
+func irredundant(primary, primes []*Ideal) ([]*Ideal, []*Ideal, error) {
+	var qs, ps []*Ideal
+	for i := range primary {
+		found := false
+		for j := range ps {
+			if idealEqual(ps[j], primes[i]) {
+				Q, err := Intersect(qs[j], primary[i])
+				if err != nil {
+					return nil, nil, err
+				}
+				if qs[j], err = inRing(Q, primary[i]); err != nil {
+					return nil, nil, err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			qs, ps = append(qs, primary[i]), append(ps, primes[i])
+		}
+	}
+	for i := 0; i < len(qs); {
+		var rest *Ideal
+		for j := range qs {
+			if j == i {
+				continue
+			}
+			if rest == nil {
+				rest = qs[j]
+			} else {
+				var err error
+				if rest, err = Intersect(rest, qs[j]); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		if rest != nil && idealContains(qs[i], rest) {
+			qs = append(qs[:i], qs[i+1:]...)
+			ps = append(ps[:i], ps[i+1:]...)
+			continue
+		}
+		i++
+	}
+	return qs, ps, nil
+}
+
+// MinimalPrimes returns the minimal associated primes of I over the
+// rationals.
+func MinimalPrimes(I *Ideal) ([]*Ideal, error) {
+	_, primes, err := PrimaryDecomposition(I)
+	if err != nil {
+		return nil, err
+	}
+	var rval []*Ideal
+	for i, P := range primes {
+		minimal := true
+		for j, R := range primes {
+			if j != i && idealContains(P, R) && (!idealContains(R, P) || j < i) {
+				minimal = false
+				break
+			}
+		}
+		if minimal {
+			rval = append(rval, P)
+		}
+	}
+	return rval, nil
+}
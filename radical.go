@@ -0,0 +1,109 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+)
+
+// InRadical reports whether some power of f is contained in the ideal I.
+// By the Rabinowitsch trick this is the case if and only if the ideal
+// I + <1 - t*f> in the ring with an additional variable t contains 1.
+func InRadical(f *Polynomial, I *Ideal) (bool, error) {
+	I, F, err := commonRing(I, NewIdeal([]*Polynomial{f}))
+	if err != nil {
+		return false, err
+	}
+	if len(F.gens) == 0 {
+		return true, nil
+	}
+	ext := newElimination(I, 1)
+	f, err = ext.lift(F.gens[0])
+	if err != nil {
+		return false, err
+	}
+	fns := []*Polynomial{ext.unit().Sub(ext.variable(0).Mul(f))}
+	for _, g := range I.gens {
+		if f, err = ext.lift(g); err != nil {
+			return false, err
+		}
+		fns = append(fns, f)
+	}
+	basis := Groebner(fns)
+	return len(basis) == 1 && basis[0].items[0].T.Degree().Sign() == 0, nil
+}
+
+// Radical calculates the radical of a zero-dimensional ideal. By
+// Seidenberg's lemma the radical is obtained by adding the square free
+// parts of the eliminants, the generators of the intersections of I with
+// the univariate polynomial rings, to the ideal.
+func Radical(I *Ideal) (*Ideal, error) {
+	basis := I.Basis()
+	if len(basis) == 0 || !isZeroDimensional(basis) {
+		return nil, errors.New("radical requires a zero-dimensional ideal")
+	}
+	fns := append([]*Polynomial{}, basis...)
+	for i := range I.vars {
+		m := minimalPolynomial(basis, i)
+		s := upolySquarefree(I.field, m)
+		if upolyDegree(s) < upolyDegree(m) {
+			fns = append(fns, upolyToPolynomial(I.field, s, I.vars, i, I.order))
+		}
+	}
+	return NewIdeal(Groebner(fns)), nil
+}
+
+// minimalPolynomial calculates the monic generator of the intersection of
+// the zero-dimensional ideal generated by the Gröbner basis with the ring
+// of the i-th variable.
+func minimalPolynomial(basis []*Polynomial, i int) []Coeff {
+	f := basis[0].field
+	t := NewTerm(len(basis[0].vars)).addExp(i, big.NewRat(1, 1))
+	x := &Polynomial{vars: basis[0].vars, order: basis[0].order, field: f,
+		items: []Monomial{{f.One(), t}}}
+	return minimalPolynomialOf(basis, x)
+}
+
+// minimalPolynomialOf calculates the monic univariate polynomial m of
+// smallest degree with m(g) in the zero-dimensional ideal generated by the
+// Gröbner basis. The normal forms of the powers of g are reduced against
+// each other until they become linearly dependent.
+func minimalPolynomialOf(basis []*Polynomial, g *Polynomial) []Coeff {
+	f := basis[0].field
+	one := NewTerm(len(basis[0].vars))
+	type row struct {
+		nf   *Polynomial
+		comb []Coeff
+	}
+	pivots := make(map[string]row)
+	cur := &Polynomial{vars: basis[0].vars, order: basis[0].order, field: f,
+		items: []Monomial{{f.One(), one}}}
+	cur = cur.NormalForm(basis)
+	for k := 0; ; k++ {
+		nf := cur
+		comb := make([]Coeff, k+1)
+		for j := range comb {
+			comb[j] = f.Zero()
+		}
+		comb[k] = f.One()
+		for j := 0; j < len(nf.items); {
+			r, ok := pivots[termKey(nf.items[j].T)]
+			if !ok {
+				j++
+				continue
+			}
+			c := nf.items[j].C
+			nf = nf.Sub(r.nf.MulMonomial(c, one))
+			comb = upolySub(f, comb, upolyScale(f, r.comb, c))
+		}
+		if nf.IsZero() {
+			return upolyMonic(f, comb)
+		}
+		inv := f.Quo(f.One(), nf.items[0].C)
+		pivots[termKey(nf.items[0].T)] = row{nf.MulMonomial(inv, one),
+			upolyScale(f, comb, inv)}
+		cur = cur.Mul(g).NormalForm(basis)
+	}
+}
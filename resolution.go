@@ -0,0 +1,320 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Resolution is a graded free resolution
+// 0 <- R/I <- F_0 <- F_1 <- ... <- F_k <- 0 of the quotient by a
+// homogeneous ideal. maps[k] is the matrix of F_(k+1) -> F_k, whose columns
+// are the images of the basis vectors, and degrees[k] contains the degrees
+// of the basis vectors of F_k.
+type Resolution struct {
+	maps    [][][]*Polynomial
+	degrees [][]int
+}
+
+// NewResolution calculates the minimal free resolution of R/I. The
+// resolution is obtained from a Schreyer frame: the syzygies of a Gröbner
+// basis, which are given by its reduced S-pairs, form a Gröbner basis with
+// respect to the order induced by the basis (Schreyer's theorem), so the
+// syzygies of the next level are again given by S-pairs. The frame is made
+// minimal by cancelling all unit entries of the matrices afterwards.
+func NewResolution(I *Ideal) (*Resolution, error) {
+	for _, f := range I.gens {
+		if _, ok := homogeneousDegree(f); !ok {
+			return nil, errors.New("resolution requires a homogeneous ideal")
+		}
+	}
+	res := &Resolution{degrees: [][]int{{0}}}
+	if len(I.gens) == 0 {
+		return res, nil
+	}
+	M := newFreeModule(I.vars, I.order, I.field, 1, true)
+	var level []*Polynomial
+	for _, g := range I.Basis() {
+		v, err := M.vector([]*Polynomial{g})
+		if err != nil {
+			return nil, err
+		}
+		level = append(level, v)
+	}
+	for len(level) > 0 {
+		res.addMap(M, level)
+		var err error
+		if M, level, err = schreyerSyzygies(M, level); err != nil {
+			return nil, err
+		}
+	}
+	res.minimize()
+	return res, nil
+}
+
+// addMap appends the map given by the vectors of the module M.
+func (res *Resolution) addMap(M *freeModule, vecs []*Polynomial) {
+	rows := M.rank
+	m := make([][]*Polynomial, rows)
+	for i := range m {
+		m[i] = make([]*Polynomial, len(vecs))
+	}
+	prev := res.degrees[len(res.degrees)-1]
+	degrees := make([]int, len(vecs))
+	for j, v := range vecs {
+		for i, f := range M.components(v) {
+			m[i][j] = f
+			if !f.IsZero() {
+				d, _ := homogeneousDegree(f)
+				degrees[j] = d + prev[i]
+			}
+		}
+	}
+	res.maps = append(res.maps, m)
+	res.degrees = append(res.degrees, degrees)
+}
+
+// schreyerSyzygies calculates the syzygies of the Gröbner basis vecs of a
+// submodule of M. For every pair of vectors whose leading terms have the
+// same position, the S-vector m_ij*v_i - m_ji*v_j is divided by vecs, and
+// the quotients q_k yield the syzygy m_ij*e_i - m_ji*e_j - sum q_k*e_k.
+// The syzygies form a Gröbner basis with respect to the Schreyer order,
+// syzygies whose leading terms are multiples of others are dropped.
+func schreyerSyzygies(M *freeModule, vecs []*Polynomial) (*freeModule, []*Polynomial, error) {
+	leads := make([]Term, len(vecs))
+	for i, v := range vecs {
+		leads[i] = v.items[0].T
+	}
+	N := M.schreyer(leads)
+	zero := &Polynomial{vars: M.vars, order: M.order, field: M.field}
+	idx := make([]int, len(M.vars))
+	for i := range idx {
+		idx[i] = M.rank + i
+	}
+	ring := func(p *Polynomial) *Polynomial {
+		f := &Polynomial{vars: M.vars, order: M.order, field: M.field}
+		for _, m := range p.items {
+			f.items = append(f.items, Monomial{m.C, m.T.remap(idx)})
+		}
+		f.normalize()
+		return f
+	}
+	monomial := func(c Coeff, t Term) *Polynomial {
+		return &Polynomial{vars: M.mvars, order: M.morder, field: M.field,
+			items: []Monomial{{c, t}}}
+	}
+	var syz []*Polynomial
+	for i := range vecs {
+		for j := i + 1; j < len(vecs); j++ {
+			a, b := leads[i], leads[j]
+			if position(a, M.rank) != position(b, M.rank) {
+				continue
+			}
+			lcm := termLCM(a, b)
+			comps := make([]*Polynomial, len(vecs))
+			for k := range comps {
+				comps[k] = zero
+			}
+			quo, _ := SPolynomial(vecs[i], vecs[j]).divide(vecs)
+			for k := range quo {
+				comps[k] = zero.Sub(ring(quo[k]))
+			}
+			ci := M.field.Quo(M.field.One(), vecs[i].items[0].C)
+			cj := M.field.Quo(M.field.One(), vecs[j].items[0].C)
+			comps[i] = comps[i].Add(ring(monomial(ci, termDiv(lcm, a))))
+			comps[j] = comps[j].Sub(ring(monomial(cj, termDiv(lcm, b))))
+			v, err := N.vector(comps)
+			if err != nil {
+				return nil, nil, err
+			}
+			syz = append(syz, v)
+		}
+	}
+	var minimal []*Polynomial
+	for i, s := range syz {
+		redundant := false
+		for j, t := range syz {
+			if i != j && termDivides(t.items[0].T, s.items[0].T) &&
+				(!termEqual(t.items[0].T, s.items[0].T) || j < i) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			minimal = append(minimal, s)
+		}
+	}
+	return N, minimal, nil
+}
+
+// schreyer returns the free module with one basis vector e_i for every
+// leading term of a Gröbner basis of a submodule of M, ordered by the
+// Schreyer order: a*e_i < b*e_j if a*leads[i] < b*leads[j] in M, or both
+// are equal and i > j.
+func (M *freeModule) schreyer(leads []Term) *freeModule {
+	N := newFreeModule(M.vars, M.order, M.field, len(leads), true)
+	idx := make([]int, len(M.mvars))
+	for i := range idx {
+		idx[i] = -1
+		if i >= M.rank {
+			idx[i] = N.rank + i - M.rank
+		}
+	}
+	N.morder = func(a, b Term) bool {
+		pa, pb := position(a, N.rank), position(b, N.rank)
+		ta := termMul(a.remap(idx), leads[pa])
+		tb := termMul(b.remap(idx), leads[pb])
+		if M.morder(ta, tb) {
+			return true
+		}
+		if M.morder(tb, ta) {
+			return false
+		}
+		return pa > pb
+	}
+	return N
+}
+
+// minimize cancels unit entries of the maps. If the entry of the map
+// F_k -> F_(k-1) in row r and column c is a unit u, the basis vector e_c of
+// F_k and the basis vector e_r of F_(k-1) are removed, the remaining
+// entries a_ij of the map are replaced by a_ij - a_rj*a_ic/u, and the
+// corresponding row and column of the neighbouring maps are dropped.
+func (res *Resolution) minimize() {
+	for k := 0; k < len(res.maps); k++ {
+		m := res.maps[k]
+		r, c := -1, -1
+		for i := range m {
+			for j := range m[i] {
+				if f := m[i][j]; len(f.items) == 1 && f.items[0].T.Degree().Sign() == 0 {
+					r, c = i, j
+				}
+			}
+		}
+		if r < 0 {
+			continue
+		}
+		u := m[r][c].items[0].C
+		f := m[r][c].field
+		for i := range m {
+			for j := range m[i] {
+				if i == r || j == c {
+					continue
+				}
+				t := m[r][j].Mul(m[i][c])
+				m[i][j] = m[i][j].Sub(t.MulMonomial(f.Quo(f.One(), u),
+					NewTerm(len(t.vars))))
+			}
+		}
+		res.maps[k] = dropColumn(dropRow(m, r), c)
+		if k > 0 {
+			res.maps[k-1] = dropColumn(res.maps[k-1], r)
+		}
+		if k+1 < len(res.maps) {
+			res.maps[k+1] = dropRow(res.maps[k+1], c)
+		}
+		res.degrees[k] = append(res.degrees[k][:r:r], res.degrees[k][r+1:]...)
+		res.degrees[k+1] = append(res.degrees[k+1][:c:c], res.degrees[k+1][c+1:]...)
+		// search the same map again
+		k--
+	}
+	for len(res.degrees) > 1 && len(res.degrees[len(res.degrees)-1]) == 0 {
+		res.degrees = res.degrees[:len(res.degrees)-1]
+		res.maps = res.maps[:len(res.maps)-1]
+	}
+}
+
+func dropRow(m [][]*Polynomial, r int) [][]*Polynomial {
+	return append(m[:r:r], m[r+1:]...)
+}
+
+func dropColumn(m [][]*Polynomial, c int) [][]*Polynomial {
+	rval := make([][]*Polynomial, len(m))
+	for i := range m {
+		rval[i] = append(m[i][:c:c], m[i][c+1:]...)
+	}
+	return rval
+}
+
+// Maps returns the matrices of the resolution as lists of rows.
+func (res *Resolution) Maps() List {
+	rval := make(List, len(res.maps))
+	for k, m := range res.maps {
+		rows := make(List, len(m))
+		for i := range m {
+			rows[i] = polynomialList(m[i])
+		}
+		rval[k] = rows
+	}
+	return rval
+}
+
+// Betti returns the graded Betti numbers of the resolution.
+func (res *Resolution) Betti() BettiTable {
+	t := BettiTable{make([]int, len(res.degrees))}
+	for k, degrees := range res.degrees {
+		for _, d := range degrees {
+			row := d - k
+			for len(t) <= row {
+				t = append(t, make([]int, len(res.degrees)))
+			}
+			t[row][k]++
+		}
+	}
+	return t
+}
+
+// BettiTable contains the graded Betti numbers b_(k,k+i) in row i and
+// column k, like the betti tables of Macaulay2.
+type BettiTable [][]int
+
+func (t BettiTable) String() string {
+	cols := 0
+	if len(t) > 0 {
+		cols = len(t[0])
+	}
+	cells := [][]string{{""}, {"total:"}}
+	for k := 0; k < cols; k++ {
+		total := 0
+		for i := range t {
+			total += t[i][k]
+		}
+		cells[0] = append(cells[0], fmt.Sprint(k))
+		cells[1] = append(cells[1], fmt.Sprint(total))
+	}
+	for i := range t {
+		row := []string{fmt.Sprintf("%d:", i)}
+		for _, b := range t[i] {
+			if b == 0 {
+				row = append(row, ".")
+			} else {
+				row = append(row, fmt.Sprint(b))
+			}
+		}
+		cells = append(cells, row)
+	}
+	width := make([]int, cols+1)
+	for _, row := range cells {
+		for j, s := range row {
+			if len(s) > width[j] {
+				width[j] = len(s)
+			}
+		}
+	}
+	buf := &bytes.Buffer{}
+	for i, row := range cells {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		for j, s := range row {
+			if j > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(buf, "%*s", width[j], s)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// signature is the leading term t*e_i of the module element which
+// represents a polynomial as a combination of the input polynomials.
+// Signatures are compared position over term: the index first and the
+// term afterwards.
+type signature struct {
+	t Term
+	i int
+}
+
+func (s signature) less(u signature, order TermOrder) bool {
+	if s.i != u.i {
+		return s.i < u.i
+	}
+	return order(s.t, u.t)
+}
+
+func (s signature) equal(u signature) bool {
+	return s.i == u.i && termEqual(s.t, u.t)
+}
+
+// divides reports whether s divides u, i.e. whether u is a multiple of s.
+func (s signature) divides(u signature) bool {
+	return s.i == u.i && termDivides(s.t, u.t)
+}
+
+func (s signature) mul(t Term) signature {
+	return signature{termMul(s.t, t), s.i}
+}
+
+// labeledPoly is a polynomial together with its signature.
+type labeledPoly struct {
+	sig signature
+	p   *Polynomial
+}
+
+// jpair is the multiple t*g of a basis element whose signature is the
+// larger one of the two multiples which form the S-polynomial of a pair.
+// Initial polynomials are stored as J-pairs without a basis element.
+type jpair struct {
+	sig  signature
+	lt   Term
+	t    Term
+	base int
+	init *Polynomial
+}
+
+// GroebnerStats counts the work done by a Gröbner basis algorithm.
+type GroebnerStats struct {
+	Name           string
+	Pairs          int
+	Skipped        int
+	Reductions     int
+	ZeroReductions int
+}
+
+// GroebnerSignature calculates the reduced Gröbner basis of the ideal
+// generated by fns using the signature-based algorithm of Gao, Volny and
+// Wang (GVW).
+func GroebnerSignature(fns []*Polynomial) []*Polynomial {
+	return groebnerSignature(fns, &GroebnerStats{})
+}
+
+// groebnerSignature implements GVW. Every polynomial is labeled with a
+// signature and is only reduced by basis elements with smaller signatures.
+// Instead of S-polynomials the algorithm reduces J-pairs, which are ordered
+// by their signatures. A J-pair is discarded without any reduction if its
+// signature is a multiple of the signature of a known syzygy (syzygy
+// criterion) or if the pair is covered by a basis element with a smaller
+// leading term and a signature dividing the signature of the pair (cover
+// criterion). For regular sequences no reduction to zero takes place.
+func groebnerSignature(fns []*Polynomial, stats *GroebnerStats) []*Polynomial {
+	var (
+		basis []labeledPoly
+		syz   []signature
+		pairs []jpair
+	)
+	if len(fns) == 0 {
+		return nil
+	}
+	order := fns[0].order
+	for i, f := range fns {
+		if !f.IsZero() {
+			pairs = append(pairs, jpair{sig: signature{NewTerm(len(f.vars)), i},
+				lt: f.items[0].T, init: f})
+		}
+	}
+	for len(pairs) > 0 {
+		// select the J-pair with the smallest signature and discard all
+		// other pairs with the same signature, which have larger leading
+		// terms and are covered whenever the selected pair is covered
+		sort.Sort(jpairSorter{pairs, order})
+		pair := pairs[0]
+		n := 1
+		for n < len(pairs) && pairs[n].sig.equal(pair.sig) {
+			n++
+		}
+		stats.Pairs += n
+		stats.Skipped += n - 1
+		pairs = pairs[n:]
+
+		if isSyzygy(syz, pair.sig) || isCovered(basis, pair, order) {
+			stats.Skipped++
+			continue
+		}
+
+		var p *Polynomial
+		if pair.init != nil {
+			p = pair.init
+		} else {
+			g := basis[pair.base].p
+			p = g.MulMonomial(g.field.One(), pair.t)
+		}
+		stats.Reductions++
+		p, singular := regularReduce(p, pair.sig, basis, order)
+		if p.IsZero() {
+			stats.ZeroReductions++
+			syz = append(syz, pair.sig)
+			continue
+		}
+		if singular {
+			continue
+		}
+
+		g := labeledPoly{pair.sig, p.Monic()}
+		for k, h := range basis {
+			lcm := termLCM(g.p.items[0].T, h.p.items[0].T)
+			tg := termDiv(lcm, g.p.items[0].T)
+			th := termDiv(lcm, h.p.items[0].T)
+			sg, sh := g.sig.mul(tg), h.sig.mul(th)
+			switch {
+			case sh.less(sg, order):
+				pairs = append(pairs, jpair{sig: sg, lt: lcm, t: tg, base: len(basis)})
+			case sg.less(sh, order):
+				pairs = append(pairs, jpair{sig: sh, lt: lcm, t: th, base: k})
+			}
+			// principal syzygies of polynomials with smaller index
+			if h.sig.i < g.sig.i {
+				syz = append(syz, g.sig.mul(h.p.items[0].T))
+			}
+		}
+		basis = append(basis, g)
+	}
+	rval := make([]*Polynomial, len(basis))
+	for i := range basis {
+		rval[i] = basis[i].p
+	}
+	return reduceBasis(rval)
+}
+
+// regularReduce top-reduces p, which has the signature sig, by all basis
+// elements whose multiples have a smaller signature. The second return
+// value reports whether the leading term of the result could be reduced by
+// a basis element with the same signature, in which case the result is
+// redundant.
+func regularReduce(p *Polynomial, sig signature, basis []labeledPoly,
+	order TermOrder) (*Polynomial, bool) {
+	for !p.IsZero() {
+		lt := p.items[0]
+		reduced, singular := false, false
+		for _, h := range basis {
+			if !termDivides(h.p.items[0].T, lt.T) {
+				continue
+			}
+			t := termDiv(lt.T, h.p.items[0].T)
+			s := h.sig.mul(t)
+			if s.less(sig, order) {
+				c := p.field.Quo(lt.C, h.p.items[0].C)
+				p = p.Sub(h.p.MulMonomial(c, t))
+				reduced = true
+				break
+			}
+			if s.equal(sig) {
+				singular = true
+			}
+		}
+		if !reduced {
+			return p, singular
+		}
+	}
+	return p, false
+}
+
+// isSyzygy reports whether sig is a multiple of the signature of a known
+// syzygy.
+func isSyzygy(syz []signature, sig signature) bool {
+	for _, s := range syz {
+		if s.divides(sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCovered reports whether a basis element g with sig(g) | sig(pair)
+// exists, whose multiple with that signature has a smaller leading term
+// than the J-pair.
+func isCovered(basis []labeledPoly, pair jpair, order TermOrder) bool {
+	if pair.init != nil {
+		return false
+	}
+	for _, g := range basis {
+		if !g.sig.divides(pair.sig) {
+			continue
+		}
+		t := termDiv(pair.sig.t, g.sig.t)
+		if order(termMul(t, g.p.items[0].T), pair.lt) {
+			return true
+		}
+	}
+	return false
+}
+
+type jpairSorter struct {
+	pairs []jpair
+	order TermOrder
+}
+
+func (s jpairSorter) Less(i, j int) bool {
+	a, b := s.pairs[i], s.pairs[j]
+	if a.sig.equal(b.sig) {
+		return s.order(a.lt, b.lt)
+	}
+	return a.sig.less(b.sig, s.order)
+}
+
+func (s jpairSorter) Swap(i, j int) {
+	s.pairs[i], s.pairs[j] = s.pairs[j], s.pairs[i]
+}
+
+func (s jpairSorter) Len() int {
+	return len(s.pairs)
+}
+
+// statsTable compares the statistics of several algorithms.
+type statsTable []*GroebnerStats
+
+func (t statsTable) String() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%-12s %8s %8s %10s %8s", "algorithm", "pairs",
+		"skipped", "reductions", "zero")
+	for _, s := range t {
+		fmt.Fprintf(buf, "\n%-12s %8d %8d %10d %8d", s.Name, s.Pairs,
+			s.Skipped, s.Reductions, s.ZeroReductions)
+	}
+	return buf.String()
+}
+
+// CompareGroebner calculates the Gröbner basis of fns with Buchberger's
+// algorithm and the signature-based algorithm and returns the statistics
+// of both runs.
+func CompareGroebner(fns []*Polynomial) statsTable {
+	b := &GroebnerStats{Name: "buchberger"}
+	groebnerBuchberger(fns, b)
+	s := &GroebnerStats{Name: "gvw"}
+	groebnerSignature(fns, s)
+	return statsTable{b, s}
+}
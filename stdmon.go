@@ -0,0 +1,92 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// quotientRing is the residue class ring R/I of a zero-dimensional ideal,
+// which is a finite dimensional vector space. Its basis consists of the
+// standard monomials, the terms which are not contained in the leading
+// term ideal of I.
+type quotientRing struct {
+	I     *Ideal
+	basis []*Polynomial
+	terms []Term
+	index map[string]int
+}
+
+func newQuotientRing(I *Ideal) (*quotientRing, error) {
+	basis := I.Basis()
+	if len(basis) == 0 || !isZeroDimensional(basis) {
+		return nil, errors.New("quotient ring requires a zero-dimensional ideal")
+	}
+	q := &quotientRing{I: I, basis: basis, index: make(map[string]int)}
+	if basis[0].items[0].T.Degree().Sign() == 0 {
+		return q, nil
+	}
+	queue := []Term{NewTerm(len(I.vars))}
+	seen := map[string]bool{termKey(queue[0]): true}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		q.terms = append(q.terms, t)
+		for i := range I.vars {
+			s := t.addExp(i, big.NewRat(1, 1))
+			if !seen[termKey(s)] && !fglmDivisible(basis, s) {
+				seen[termKey(s)] = true
+				queue = append(queue, s)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(termSorter{q.terms, I.order}))
+	for i, t := range q.terms {
+		q.index[termKey(t)] = i
+	}
+	return q, nil
+}
+
+// StandardMonomials returns the standard monomials in increasing order.
+func (q *quotientRing) StandardMonomials() []*Polynomial {
+	rval := make([]*Polynomial, len(q.terms))
+	for i, t := range q.terms {
+		rval[i] = &Polynomial{vars: q.I.vars, order: q.I.order, field: q.I.field,
+			items: []Monomial{{q.I.field.One(), t}}}
+	}
+	return rval
+}
+
+// coordinates returns the coefficients of the normal form of f with respect
+// to the standard monomials.
+func (q *quotientRing) coordinates(f *Polynomial) []Coeff {
+	v := make([]Coeff, len(q.terms))
+	for i := range v {
+		v[i] = q.I.field.Zero()
+	}
+	for _, m := range f.NormalForm(q.basis).items {
+		v[q.index[termKey(m.T)]] = m.C
+	}
+	return v
+}
+
+// MultMatrix returns the matrix of the multiplication by f with respect to
+// the standard monomials. The j-th column contains the coordinates of f
+// times the j-th standard monomial. Its eigenvalues are the values of f at
+// the solutions of the ideal, counted with multiplicity.
+func (q *quotientRing) MultMatrix(f *Polynomial) [][]Coeff {
+	m := make([][]Coeff, len(q.terms))
+	for i := range m {
+		m[i] = make([]Coeff, len(q.terms))
+	}
+	for j, t := range q.terms {
+		v := q.coordinates(f.MulMonomial(q.I.field.One(), t))
+		for i := range v {
+			m[i][j] = v[i]
+		}
+	}
+	return m
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// permute applies the permutation perm of the variables of p to p, which
+// replaces the i-th variable by the perm[i]-th variable.
+func (p *Polynomial) permute(perm []int) *Polynomial {
+	inv := make([]int, len(perm))
+	for i, j := range perm {
+		inv[j] = i
+	}
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	rval.items = make([]Monomial, len(p.items))
+	for i, m := range p.items {
+		rval.items[i] = Monomial{m.C, m.T.remap(inv)}
+	}
+	rval.normalize()
+	return rval
+}
+
+// IsSymmetric reports whether p is invariant under all permutations of its
+// variables. It suffices to check the transpositions of neighbouring
+// variables, which generate the symmetric group.
+func IsSymmetric(p *Polynomial) bool {
+	perm := make([]int, len(p.vars))
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := 0; i+1 < len(perm); i++ {
+		perm[i], perm[i+1] = perm[i+1], perm[i]
+		if !p.permute(perm).Equal(p) {
+			return false
+		}
+		perm[i], perm[i+1] = perm[i+1], perm[i]
+	}
+	return true
+}
+
+// elementarySymmetric returns the elementary symmetric polynomials
+// e_1, ..., e_n in the first n variables of a ring with the given
+// variables. They are the coefficients of the product of all 1 + x_i*T.
+func elementarySymmetric(n int, vars []string, order TermOrder, field Field) []*Polynomial {
+	e := make([]*Polynomial, n+1)
+	for k := range e {
+		e[k] = &Polynomial{vars: vars, order: order, field: field}
+	}
+	e[0].items = []Monomial{{field.One(), NewTerm(len(vars))}}
+	for i := 0; i < n; i++ {
+		x := &Polynomial{vars: vars, order: order, field: field,
+			items: []Monomial{{field.One(), NewTerm(len(vars)).addExp(i, ratOne)}}}
+		for k := i + 1; k > 0; k-- {
+			e[k] = e[k].Add(x.Mul(e[k-1]))
+		}
+	}
+	return e[1:]
+}
+
+// ToSymmetric expresses the symmetric polynomial p in the elementary
+// symmetric polynomials e1, ..., en of its variables. The remainder of p
+// modulo the lex Gröbner basis of the relations e_k - e_k(x), in which the
+// variables x are bigger than the variables e, only contains the variables
+// e and is the unique representation.
+func ToSymmetric(p *Polynomial) (*Polynomial, error) {
+	n := len(p.vars)
+	names := make([]string, n)
+	for k := range names {
+		names[k] = "e" + strconv.Itoa(k+1)
+		for _, v := range p.vars {
+			if v == names[k] {
+				return nil, fmt.Errorf("variable %s already occurs in %v", v, p)
+			}
+		}
+	}
+	vars := append(append([]string{}, p.vars...), names...)
+	f, err := p.embed(vars, LexTermOrder)
+	if err != nil {
+		return nil, err
+	}
+	rels := elementarySymmetric(n, vars, LexTermOrder, p.field)
+	for k := range rels {
+		e := &Polynomial{vars: vars, order: LexTermOrder, field: p.field,
+			items: []Monomial{{p.field.One(), NewTerm(len(vars)).addExp(n+k, ratOne)}}}
+		rels[k] = e.Sub(rels[k])
+	}
+	_, r := f.divide(Groebner(rels))
+	for _, m := range r.items {
+		for i := 0; i < n; i++ {
+			if m.T.Sign(i) != 0 {
+				return nil, fmt.Errorf("polynomial %v is not symmetric", p)
+			}
+		}
+	}
+
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	idx := make([]int, n)
+	for i, v := range sorted {
+		for k := range names {
+			if names[k] == v {
+				idx[i] = n + k
+			}
+		}
+	}
+	rval := &Polynomial{vars: sorted, order: p.order, field: p.field}
+	for _, m := range r.items {
+		rval.items = append(rval.items, Monomial{m.C, m.T.remap(idx)})
+	}
+	rval.normalize()
+	return rval, nil
+}
+
+// Reynolds applies the Reynolds operator of the permutation group generated
+// by gens to p, i.e. the average of all images of p under the group. Every
+// permutation is given by the images of its variables in increasing order,
+// e.g. [y, x, z] swaps x and y. The result is invariant under the group.
+func Reynolds(p *Polynomial, gens [][]string) (*Polynomial, error) {
+	vars := append([]string{}, p.vars...)
+	for _, g := range gens {
+		vars = append(vars, g...)
+	}
+	sort.Strings(vars)
+	n := 0
+	for i := range vars {
+		if i == 0 || vars[i] != vars[i-1] {
+			vars[n] = vars[i]
+			n++
+		}
+	}
+	vars = vars[:n]
+	f, err := p.embed(vars, p.order)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make([][]int, len(gens))
+	for k, g := range gens {
+		dom := append([]string{}, g...)
+		sort.Strings(dom)
+		perms[k] = make([]int, n)
+		for i := range perms[k] {
+			perms[k][i] = i
+		}
+		for i, v := range dom {
+			if i > 0 && v == dom[i-1] {
+				return nil, fmt.Errorf("invalid permutation %v", g)
+			}
+			perms[k][sort.SearchStrings(vars, v)] = sort.SearchStrings(vars, g[i])
+		}
+	}
+
+	// enumerate the group by closing the identity under the generators
+	key := func(perm []int) string { return fmt.Sprint(perm) }
+	id := make([]int, n)
+	for i := range id {
+		id[i] = i
+	}
+	group := [][]int{id}
+	found := map[string]bool{key(id): true}
+	for k := 0; k < len(group); k++ {
+		for _, g := range perms {
+			h := make([]int, n)
+			for i := range h {
+				h[i] = g[group[k][i]]
+			}
+			if !found[key(h)] {
+				found[key(h)] = true
+				group = append(group, h)
+			}
+		}
+	}
+
+	c, err := p.field.FromRat(big.NewRat(1, int64(len(group))))
+	if err != nil || p.field.IsZero(c) {
+		return nil, fmt.Errorf("the order %d of the group is divisible by the characteristic", len(group))
+	}
+	rval := &Polynomial{vars: vars, order: p.order, field: p.field}
+	for _, g := range group {
+		rval = rval.Add(f.permute(g))
+	}
+	return rval.MulMonomial(c, NewTerm(n)), nil
+}
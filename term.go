@@ -0,0 +1,291 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Term is the exponent vector of a monomial. Exponents are usually small
+// integers, which are stored in a compact []int32 together with the cached
+// total degree, so that comparing terms does not need any allocations.
+// Terms with exponents that do not fit into an int32 fall back to a slice
+// of rational numbers. Every constructor chooses the compact representation
+// whenever possible, so two equal terms always use the same representation.
+// Terms are immutable, all operations return new terms.
+type Term struct {
+	exp []int32
+	deg int64
+	big []big.Rat
+}
+
+// NewTerm returns the term of degree zero in n variables.
+func NewTerm(n int) Term {
+	return Term{exp: make([]int32, n)}
+}
+
+// termFromRats creates a term from arbitrary rational exponents.
+func termFromRats(r []big.Rat) Term {
+	t := Term{exp: make([]int32, len(r))}
+	for i := range r {
+		if !r[i].IsInt() || !r[i].Num().IsInt64() {
+			return Term{big: r}
+		}
+		x := r[i].Num().Int64()
+		if x < math.MinInt32 || x > math.MaxInt32 {
+			return Term{big: r}
+		}
+		t.exp[i] = int32(x)
+		t.deg += x
+	}
+	return t
+}
+
+// compact reports whether t uses the compact representation.
+func (t Term) compact() bool {
+	return t.big == nil
+}
+
+// Len returns the number of variables.
+func (t Term) Len() int {
+	if t.compact() {
+		return len(t.exp)
+	}
+	return len(t.big)
+}
+
+// Exp returns the exponent of the i-th variable.
+func (t Term) Exp(i int) *big.Rat {
+	if t.compact() {
+		return big.NewRat(int64(t.exp[i]), 1)
+	}
+	return new(big.Rat).Set(&t.big[i])
+}
+
+// Int returns the exponent of the i-th variable if it is an integer.
+func (t Term) Int(i int) (int, bool) {
+	if t.compact() {
+		return int(t.exp[i]), true
+	}
+	if !t.big[i].IsInt() || t.big[i].Num().BitLen() > 31 {
+		return 0, false
+	}
+	return int(t.big[i].Num().Int64()), true
+}
+
+// Sign returns the sign of the exponent of the i-th variable.
+func (t Term) Sign(i int) int {
+	if t.compact() {
+		switch {
+		case t.exp[i] < 0:
+			return -1
+		case t.exp[i] > 0:
+			return 1
+		}
+		return 0
+	}
+	return t.big[i].Sign()
+}
+
+// Degree returns the total degree of t.
+func (t Term) Degree() *big.Rat {
+	if t.compact() {
+		return big.NewRat(t.deg, 1)
+	}
+	d := new(big.Rat)
+	for i := range t.big {
+		d.Add(d, &t.big[i])
+	}
+	return d
+}
+
+// rats returns a copy of the exponents as rational numbers.
+func (t Term) rats() []big.Rat {
+	r := make([]big.Rat, t.Len())
+	if t.compact() {
+		for i := range r {
+			r[i].SetInt64(int64(t.exp[i]))
+		}
+	} else {
+		for i := range r {
+			r[i].Set(&t.big[i])
+		}
+	}
+	return r
+}
+
+// addExp returns a copy of t where e was added to the exponent of the i-th
+// variable.
+func (t Term) addExp(i int, e *big.Rat) Term {
+	r := t.rats()
+	r[i].Add(&r[i], e)
+	return termFromRats(r)
+}
+
+// remap returns a term in len(idx) variables whose j-th exponent is the
+// idx[j]-th exponent of t, or zero if idx[j] is negative.
+func (t Term) remap(idx []int) Term {
+	if t.compact() {
+		u := Term{exp: make([]int32, len(idx))}
+		for j := range idx {
+			if idx[j] >= 0 {
+				u.exp[j] = t.exp[idx[j]]
+				u.deg += int64(u.exp[j])
+			}
+		}
+		return u
+	}
+	r := make([]big.Rat, len(idx))
+	for j := range idx {
+		if idx[j] >= 0 {
+			r[j].Set(&t.big[idx[j]])
+		}
+	}
+	return termFromRats(r)
+}
+
+// termCombine applies op to all exponents of a and b. The compact
+// representation is used unless one of the results overflows.
+func termCombine(a, b Term, op func(x, y int64) int64,
+	bigOp func(z, x, y *big.Rat)) Term {
+	if a.compact() && b.compact() {
+		t := Term{exp: make([]int32, len(a.exp))}
+		overflow := false
+		for i := range t.exp {
+			x := op(int64(a.exp[i]), int64(b.exp[i]))
+			if x < math.MinInt32 || x > math.MaxInt32 {
+				overflow = true
+				break
+			}
+			t.exp[i] = int32(x)
+			t.deg += x
+		}
+		if !overflow {
+			return t
+		}
+	}
+	r, s := a.rats(), b.rats()
+	for i := range r {
+		bigOp(&r[i], &r[i], &s[i])
+	}
+	return termFromRats(r)
+}
+
+func termMul(a, b Term) Term {
+	return termCombine(a, b, func(x, y int64) int64 {
+		return x + y
+	}, func(z, x, y *big.Rat) {
+		z.Add(x, y)
+	})
+}
+
+// termDiv calculates a / b. The result is only valid if b divides a.
+func termDiv(a, b Term) Term {
+	return termCombine(a, b, func(x, y int64) int64 {
+		return x - y
+	}, func(z, x, y *big.Rat) {
+		z.Sub(x, y)
+	})
+}
+
+func termLCM(a, b Term) Term {
+	return termCombine(a, b, func(x, y int64) int64 {
+		if x >= y {
+			return x
+		}
+		return y
+	}, func(z, x, y *big.Rat) {
+		if x.Cmp(y) < 0 {
+			x = y
+		}
+		z.Set(x)
+	})
+}
+
+// termDivides reports whether a divides b.
+func termDivides(a, b Term) bool {
+	if a.compact() && b.compact() {
+		if a.deg > b.deg {
+			return false
+		}
+		for i := range a.exp {
+			if a.exp[i] > b.exp[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i < a.Len(); i++ {
+		if a.Exp(i).Cmp(b.Exp(i)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// termCoprime reports whether a and b do not share any variable.
+func termCoprime(a, b Term) bool {
+	for i := 0; i < a.Len(); i++ {
+		if a.Sign(i) != 0 && b.Sign(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func termEqual(a, b Term) bool {
+	if a.compact() != b.compact() {
+		return false
+	}
+	if a.compact() {
+		if a.deg != b.deg || len(a.exp) != len(b.exp) {
+			return false
+		}
+		for i := range a.exp {
+			if a.exp[i] != b.exp[i] {
+				return false
+			}
+		}
+		return true
+	}
+	if len(a.big) != len(b.big) {
+		return false
+	}
+	for i := range a.big {
+		if a.big[i].Cmp(&b.big[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// termCmp compares the exponents of the i-th variable of a and b.
+func termCmp(a, b Term, i int) int {
+	if a.compact() && b.compact() {
+		switch {
+		case a.exp[i] < b.exp[i]:
+			return -1
+		case a.exp[i] > b.exp[i]:
+			return 1
+		}
+		return 0
+	}
+	return a.Exp(i).Cmp(b.Exp(i))
+}
+
+// termKey returns a string which identifies the term, e.g. for using it as
+// key of a map.
+func termKey(t Term) string {
+	if t.compact() {
+		return fmt.Sprint(t.exp)
+	}
+	buf := &bytes.Buffer{}
+	for i := range t.big {
+		fmt.Fprintf(buf, "%s ", t.big[i].RatString())
+	}
+	return buf.String()
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"sort"
+)
+
+// The functions in this file implement the characteristic set method of
+// Ritt and Wu. The variables are ordered like in the lexicographical term
+// order, i.e. the first variable is the largest one. The main variable of a
+// non-constant polynomial is the largest variable occurring in it, and
+// polynomials are ranked by their main variable first and by their degree
+// in the main variable afterwards. An ascending chain is a list of
+// polynomials with increasing main variables, where every polynomial is
+// reduced with respect to the previous ones, i.e. its degree in their main
+// variables is smaller than theirs.
+
+// mainVar returns the index of the main variable of p, or len(p.vars) if p
+// is constant.
+func mainVar(p *Polynomial) int {
+	for i := range p.vars {
+		for _, m := range p.items {
+			if m.T.Sign(i) != 0 {
+				return i
+			}
+		}
+	}
+	return len(p.vars)
+}
+
+// rankLess reports whether the rank of p is lower than the rank of q.
+func rankLess(p, q *Polynomial) bool {
+	vp, vq := mainVar(p), mainVar(q)
+	if vp != vq {
+		return vp > vq
+	}
+	if vp == len(p.vars) {
+		return false
+	}
+	dp, _ := p.degreeIn(vp)
+	dq, _ := q.degreeIn(vq)
+	return dp < dq
+}
+
+// initial returns the leading coefficient of p with respect to its main
+// variable.
+func initial(p *Polynomial) *Polynomial {
+	v := mainVar(p)
+	if v == len(p.vars) {
+		return p
+	}
+	d, _ := p.degreeIn(v)
+	return p.coeffIn(v, d)
+}
+
+// reducedWrt reports whether p is reduced with respect to the chain, i.e.
+// whether its degree in the main variable of every chain element is lower.
+func reducedWrt(p *Polynomial, chain []*Polynomial) bool {
+	for _, c := range chain {
+		v := mainVar(c)
+		if v == len(c.vars) {
+			return false
+		}
+		dp, _ := p.degreeIn(v)
+		dc, _ := c.degreeIn(v)
+		if dp >= dc {
+			return false
+		}
+	}
+	return true
+}
+
+type rankSorter []*Polynomial
+
+func (s rankSorter) Less(i, j int) bool {
+	return rankLess(s[i], s[j])
+}
+
+func (s rankSorter) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s rankSorter) Len() int {
+	return len(s)
+}
+
+// basicSet selects an ascending chain of lowest rank from fns.
+func basicSet(fns []*Polynomial) []*Polynomial {
+	sorted := append([]*Polynomial{}, fns...)
+	sort.Stable(rankSorter(sorted))
+	var chain []*Polynomial
+	for _, f := range sorted {
+		if len(chain) == 0 {
+			chain = append(chain, f)
+			if mainVar(f) == len(f.vars) {
+				break
+			}
+			continue
+		}
+		if mainVar(f) < mainVar(chain[len(chain)-1]) && reducedWrt(f, chain) {
+			chain = append(chain, f)
+		}
+	}
+	return chain
+}
+
+// chainRemainder calculates the successive pseudo-remainder of p with
+// respect to the ascending chain, starting with the element of the highest
+// main variable.
+func chainRemainder(p *Polynomial, chain []*Polynomial) (*Polynomial, error) {
+	for i := len(chain) - 1; i >= 0 && !p.IsZero(); i-- {
+		_, r, err := p.PseudoDivide(chain[i], mainVar(chain[i]))
+		if err != nil {
+			return nil, err
+		}
+		p = r
+	}
+	if !p.IsZero() {
+		p = p.Monic()
+	}
+	return p, nil
+}
+
+// CharacteristicSet calculates a characteristic set of fns with Wu's
+// algorithm: the basic set of the polynomials is extended by the non-zero
+// remainders of all polynomials until every polynomial reduces to zero.
+// The characteristic set C satisfies Zero(C / I) ⊆ Zero(fns) ⊆ Zero(C),
+// where I is the product of the initials of C.
+func CharacteristicSet(fns []*Polynomial) ([]*Polynomial, error) {
+	var set []*Polynomial
+	for _, f := range fns {
+		if !f.IsZero() {
+			set = append(set, f.Monic())
+		}
+	}
+	if len(set) == 0 {
+		return nil, nil
+	}
+	for {
+		chain := basicSet(set)
+		if mainVar(chain[0]) == len(chain[0].vars) {
+			return chain, nil
+		}
+		var rems []*Polynomial
+		for _, f := range set {
+			r, err := chainRemainder(f, chain)
+			if err != nil {
+				return nil, err
+			}
+			if !r.IsZero() {
+				rems = append(rems, r)
+			}
+		}
+		if len(rems) == 0 {
+			return chain, nil
+		}
+		set = append(set, rems...)
+	}
+}
+
+// Triangularize decomposes the zero set of fns into the zero sets of
+// triangular systems (Wu's zero decomposition): the zeros of fns are the
+// zeros of its characteristic set C, at which no initial of C vanishes,
+// together with the zeros of fns + <I> for every non-constant initial I of
+// C. Inconsistent systems, which contain a non-zero constant, are dropped.
+func Triangularize(fns []*Polynomial) ([][]*Polynomial, error) {
+	var rval [][]*Polynomial
+	for _, f := range fns {
+		for i := range f.vars {
+			if _, ok := f.degreeIn(i); !ok {
+				return nil, errors.New("triangularize requires non-negative integer exponents")
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	var decompose func(fns []*Polynomial) error
+	decompose = func(fns []*Polynomial) error {
+		chain, err := CharacteristicSet(fns)
+		if err != nil {
+			return err
+		}
+		if len(chain) > 0 && mainVar(chain[0]) == len(chain[0].vars) {
+			return nil
+		}
+		key := polynomialList(chain).String()
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		rval = append(rval, chain)
+		for _, c := range chain {
+			I := initial(c)
+			if mainVar(I) == len(I.vars) {
+				continue
+			}
+			next := append(append([]*Polynomial{}, fns...), chain...)
+			if err := decompose(append(next, I)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := decompose(fns); err != nil {
+		return nil, err
+	}
+	return rval, nil
+}
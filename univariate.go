@@ -0,0 +1,274 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// The functions in this file implement the arithmetic of dense univariate
+// polynomials over a field. A polynomial is stored as a slice of
+// coefficients, starting with the constant term. Normalized polynomials do
+// not have trailing zero coefficients, so the zero polynomial is an empty
+// slice.
+
+func upolyTrim(f Field, a []Coeff) []Coeff {
+	n := len(a)
+	for n > 0 && f.IsZero(a[n-1]) {
+		n--
+	}
+	return a[:n]
+}
+
+func upolyDegree(a []Coeff) int {
+	return len(a) - 1
+}
+
+func upolyAdd(f Field, a, b []Coeff) []Coeff {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	c := make([]Coeff, len(a))
+	for i := range a {
+		if i < len(b) {
+			c[i] = f.Add(a[i], b[i])
+		} else {
+			c[i] = a[i]
+		}
+	}
+	return upolyTrim(f, c)
+}
+
+func upolySub(f Field, a, b []Coeff) []Coeff {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	c := make([]Coeff, n)
+	for i := range c {
+		switch {
+		case i < len(a) && i < len(b):
+			c[i] = f.Sub(a[i], b[i])
+		case i < len(a):
+			c[i] = a[i]
+		default:
+			c[i] = f.Neg(b[i])
+		}
+	}
+	return upolyTrim(f, c)
+}
+
+// upolyScale multiplies every coefficient of a with s.
+func upolyScale(f Field, a []Coeff, s Coeff) []Coeff {
+	c := make([]Coeff, len(a))
+	for i := range a {
+		c[i] = f.Mul(a[i], s)
+	}
+	return upolyTrim(f, c)
+}
+
+// upolyMul multiplies a and b, large polynomials are multiplied with
+// Karatsuba's algorithm.
+func upolyMul(f Field, a, b []Coeff) []Coeff {
+	if len(a) >= karatsubaThreshold && len(b) >= karatsubaThreshold {
+		return upolyKaratsuba(f, a, b)
+	}
+	return upolyMulClassical(f, a, b)
+}
+
+func upolyMulClassical(f Field, a, b []Coeff) []Coeff {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	c := make([]Coeff, len(a)+len(b)-1)
+	for i := range c {
+		c[i] = f.Zero()
+	}
+	for i := range a {
+		if f.IsZero(a[i]) {
+			continue
+		}
+		for j := range b {
+			c[i+j] = f.Add(c[i+j], f.Mul(a[i], b[j]))
+		}
+	}
+	return upolyTrim(f, c)
+}
+
+// upolyDivMod calculates the quotient and remainder of the division of a
+// by the non-zero polynomial b. Large quotients are calculated with Newton
+// iteration.
+func upolyDivMod(f Field, a, b []Coeff) (q, r []Coeff) {
+	if len(b) >= newtonThreshold && len(a)-len(b) >= newtonThreshold {
+		return upolyFastDivMod(f, a, b)
+	}
+	return upolyDivModClassical(f, a, b)
+}
+
+func upolyDivModClassical(f Field, a, b []Coeff) (q, r []Coeff) {
+	r = make([]Coeff, len(a))
+	copy(r, a)
+	if len(a) < len(b) {
+		return nil, r
+	}
+	q = make([]Coeff, len(a)-len(b)+1)
+	lc := b[len(b)-1]
+	for i := len(q) - 1; i >= 0; i-- {
+		c := f.Quo(r[i+len(b)-1], lc)
+		q[i] = c
+		if f.IsZero(c) {
+			continue
+		}
+		for j := range b {
+			r[i+j] = f.Sub(r[i+j], f.Mul(c, b[j]))
+		}
+	}
+	return upolyTrim(f, q), upolyTrim(f, r[:len(b)-1])
+}
+
+func upolyMod(f Field, a, b []Coeff) []Coeff {
+	_, r := upolyDivMod(f, a, b)
+	return r
+}
+
+// upolyMonic divides a by its leading coefficient.
+func upolyMonic(f Field, a []Coeff) []Coeff {
+	if len(a) == 0 {
+		return a
+	}
+	return upolyScale(f, a, f.Quo(f.One(), a[len(a)-1]))
+}
+
+// upolyGCD calculates the monic greatest common divisor of a and b. The
+// half-GCD algorithm is used for large polynomials.
+func upolyGCD(f Field, a, b []Coeff) []Coeff {
+	if len(a) >= halfGCDThreshold && len(b) >= halfGCDThreshold {
+		return upolyFastGCD(f, a, b)
+	}
+	for len(b) > 0 {
+		a, b = b, upolyMod(f, a, b)
+	}
+	return upolyMonic(f, a)
+}
+
+// upolyExtGCD calculates the monic greatest common divisor g of a and b
+// together with a polynomial s, so that s*a = g modulo b.
+func upolyExtGCD(f Field, a, b []Coeff) (g, s []Coeff) {
+	r0, r1 := a, b
+	s0, s1 := []Coeff{f.One()}, []Coeff(nil)
+	for len(r1) > 0 {
+		q, r := upolyDivMod(f, r0, r1)
+		r0, r1 = r1, r
+		s0, s1 = s1, upolySub(f, s0, upolyMul(f, q, s1))
+	}
+	if len(r0) == 0 {
+		return r0, s0
+	}
+	inv := f.Quo(f.One(), r0[len(r0)-1])
+	return upolyScale(f, r0, inv), upolyScale(f, s0, inv)
+}
+
+// upolyPowMod calculates a^e modulo m by repeated squaring.
+func upolyPowMod(f Field, a []Coeff, e *big.Int, m []Coeff) []Coeff {
+	rval := upolyMod(f, []Coeff{f.One()}, m)
+	a = upolyMod(f, a, m)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		rval = upolyMod(f, upolyMul(f, rval, rval), m)
+		if e.Bit(i) == 1 {
+			rval = upolyMod(f, upolyMul(f, rval, a), m)
+		}
+	}
+	return rval
+}
+
+func upolyEqual(f Field, a, b []Coeff) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !f.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// upolyDeriv calculates the formal derivative of a.
+func upolyDeriv(f Field, a []Coeff) []Coeff {
+	if len(a) == 0 {
+		return nil
+	}
+	c := make([]Coeff, len(a)-1)
+	for i := range c {
+		n, _ := f.FromRat(big.NewRat(int64(i+1), 1))
+		c[i] = f.Mul(n, a[i+1])
+	}
+	return upolyTrim(f, c)
+}
+
+// upolySquarefree calculates the monic square free part of a, the product
+// of its distinct irreducible factors. a/gcd(a, a') contains all factors
+// whose multiplicity is not divisible by the characteristic p. The other
+// factors remain in the gcd. A polynomial with vanishing derivative is a
+// polynomial in x^p and therefore a p-th power.
+func upolySquarefree(f Field, a []Coeff) []Coeff {
+	if upolyDegree(a) < 1 {
+		return upolyMonic(f, a)
+	}
+	d := upolyDeriv(f, a)
+	if len(d) == 0 {
+		p := int(characteristic(f))
+		root := make([]Coeff, 0, len(a)/p+1)
+		for i := 0; i < len(a); i += p {
+			root = append(root, pthRoot(f, a[i]))
+		}
+		return upolySquarefree(f, root)
+	}
+	g := upolyGCD(f, a, d)
+	s, _ := upolyDivMod(f, a, g)
+	if upolyDegree(g) < 1 {
+		return upolyMonic(f, s)
+	}
+	r := upolySquarefree(f, g)
+	lcm, _ := upolyDivMod(f, upolyMul(f, s, r), upolyGCD(f, s, r))
+	return upolyMonic(f, lcm)
+}
+
+// upolyFromPolynomial converts a polynomial with at most one variable into
+// the dense representation.
+func upolyFromPolynomial(p *Polynomial) ([]Coeff, error) {
+	if len(p.vars) > 1 {
+		return nil, fmt.Errorf("polynomial %v is not univariate", p)
+	}
+	var a []Coeff
+	for _, m := range p.items {
+		e := 0
+		if len(p.vars) == 1 {
+			var ok bool
+			if e, ok = m.T.Int(0); !ok || e < 0 || e > 1<<24 {
+				return nil, fmt.Errorf("invalid exponent %v", m.T.Exp(0).RatString())
+			}
+		}
+		for len(a) <= e {
+			a = append(a, p.field.Zero())
+		}
+		a[e] = p.field.Add(a[e], m.C)
+	}
+	return upolyTrim(p.field, a), nil
+}
+
+// upolyToPolynomial converts a into a polynomial in the variables vars,
+// in which only the i-th variable occurs.
+func upolyToPolynomial(f Field, a []Coeff, vars []string, i int, order TermOrder) *Polynomial {
+	p := &Polynomial{vars: vars, order: order, field: f}
+	for e := range a {
+		if !f.IsZero(a[e]) {
+			t := NewTerm(len(vars)).addExp(i, big.NewRat(int64(e), 1))
+			p.items = append(p.items, Monomial{a[e], t})
+		}
+	}
+	p.normalize()
+	return p
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2014 by Christoph Hack <christoph@tux21b.org>
+// All rights reserved. Distributed under the Simplified BSD License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// matrixOrder is a term order given by a matrix with one column for every
+// variable. Terms are compared by the weights of the first row, ties are
+// broken by the following rows. A weight order is a matrix order whose
+// first row is the weight vector.
+type matrixOrder [][]*big.Rat
+
+// intWeights scales every row of m to a vector of small integers, which
+// defines the same order. The second return value is false if the weights
+// of compact terms might overflow an int64.
+func (m matrixOrder) intWeights() ([][]int64, bool) {
+	if len(m) == 0 || len(m[0]) == 0 || len(m[0]) > 64 {
+		return nil, false
+	}
+	rval := make([][]int64, len(m))
+	for i, row := range m {
+		den := big.NewInt(1)
+		for _, x := range row {
+			g := new(big.Int).GCD(nil, nil, den, x.Denom())
+			den.Mul(den, new(big.Int).Quo(x.Denom(), g))
+		}
+		rval[i] = make([]int64, len(row))
+		for j, x := range row {
+			y := new(big.Int).Mul(x.Num(), den)
+			y.Quo(y, x.Denom())
+			if y.BitLen() > 24 {
+				return nil, false
+			}
+			rval[i][j] = y.Int64()
+		}
+	}
+	return rval, true
+}
+
+// newMatrixOrder checks that rows defines a term order on terms in n
+// variables: the matrix must have full rank and the first non-zero entry
+// of every column must be positive.
+func newMatrixOrder(rows [][]*big.Rat, n int) (matrixOrder, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("empty order matrix")
+	}
+	for _, row := range rows {
+		if len(row) != n {
+			return nil, fmt.Errorf("order matrix needs %d columns", n)
+		}
+	}
+	for j := 0; j < n; j++ {
+		for i := range rows {
+			if x := rows[i][j].Sign(); x < 0 {
+				return nil, errors.New("order matrix is not a well-ordering")
+			} else if x > 0 {
+				break
+			}
+		}
+	}
+	if matrixRank(rows) != n {
+		return nil, errors.New("order matrix is singular")
+	}
+	return matrixOrder(rows), nil
+}
+
+// namedOrderMatrix returns the matrix of a named term order in n variables.
+func namedOrderMatrix(name string, n int) (matrixOrder, error) {
+	rows := make([][]*big.Rat, 0, n+1)
+	unit := func(i int, sign int64) []*big.Rat {
+		row := make([]*big.Rat, n)
+		for j := range row {
+			row[j] = new(big.Rat)
+		}
+		row[i].SetInt64(sign)
+		return row
+	}
+	ones := make([]*big.Rat, n)
+	for j := range ones {
+		ones[j] = big.NewRat(1, 1)
+	}
+	switch name {
+	case "lex":
+		for i := 0; i < n; i++ {
+			rows = append(rows, unit(i, 1))
+		}
+	case "lexrev":
+		for i := n - 1; i >= 0; i-- {
+			rows = append(rows, unit(i, 1))
+		}
+	case "total":
+		rows = append(rows, ones)
+		for i := 0; i < n; i++ {
+			rows = append(rows, unit(i, 1))
+		}
+	case "grevlex":
+		rows = append(rows, ones)
+		for i := n - 1; i > 0; i-- {
+			rows = append(rows, unit(i, -1))
+		}
+	default:
+		return nil, fmt.Errorf("unknown term order %q", name)
+	}
+	return matrixOrder(rows), nil
+}
+
+// matrixRank calculates the rank of a matrix by gaussian elimination.
+func matrixRank(rows [][]*big.Rat) int {
+	m := make([][]*big.Rat, len(rows))
+	for i := range rows {
+		m[i] = make([]*big.Rat, len(rows[i]))
+		for j := range rows[i] {
+			m[i][j] = new(big.Rat).Set(rows[i][j])
+		}
+	}
+	rank := 0
+	for j := 0; len(m) > 0 && j < len(m[0]); j++ {
+		k := rank
+		for k < len(m) && m[k][j].Sign() == 0 {
+			k++
+		}
+		if k == len(m) {
+			continue
+		}
+		m[rank], m[k] = m[k], m[rank]
+		for i := rank + 1; i < len(m); i++ {
+			c := new(big.Rat).Quo(m[i][j], m[rank][j])
+			for l := j; l < len(m[i]); l++ {
+				m[i][l].Sub(m[i][l], new(big.Rat).Mul(c, m[rank][l]))
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+// weight calculates the scalar product of the weight vector w and the
+// exponents of t.
+func weight(w []*big.Rat, t Term) *big.Rat {
+	rval, x := new(big.Rat), new(big.Rat)
+	for i := range w {
+		if w[i].Sign() != 0 && t.Sign(i) != 0 {
+			rval.Add(rval, x.Mul(w[i], t.Exp(i)))
+		}
+	}
+	return rval
+}
+
+// TermOrder returns the term order of the matrix. Compact terms are
+// compared using integer arithmetic if the weights are small.
+func (m matrixOrder) TermOrder() TermOrder {
+	ints, ok := m.intWeights()
+	return func(a, b Term) bool {
+		if ok && a.compact() && b.compact() {
+			for _, w := range ints {
+				var x int64
+				for j := range w {
+					x += w[j] * int64(a.exp[j]-b.exp[j])
+				}
+				if x != 0 {
+					return x < 0
+				}
+			}
+			return false
+		}
+		for _, w := range m {
+			if x := weight(w, a).Cmp(weight(w, b)); x != 0 {
+				return x < 0
+			}
+		}
+		return false
+	}
+}
+
+// refine returns the weight order with the weight w whose ties are broken
+// by m.
+func (m matrixOrder) refine(w []*big.Rat) matrixOrder {
+	rval := make(matrixOrder, 0, len(m)+1)
+	rval = append(rval, w)
+	return append(rval, m...)
+}
+
+// Walk converts the Gröbner basis fns with respect to the term order from
+// into the reduced Gröbner basis with respect to the term order to. The
+// Gröbner walk (Collart, Kalkbrener and Mall) follows the straight line
+// w(s) = (1-s)*u + s*v between the weight vectors u and v of both orders
+// through the Gröbner fan. Whenever the line leaves the cone of the
+// current basis, the initial forms with respect to the weight w(s) are
+// converted into a Gröbner basis with respect to the next order, which
+// usually is a small computation since the initial forms consist of only
+// a few terms. The new basis is obtained by lifting the result back into
+// the ideal. The ideal does not need to be zero-dimensional.
+func Walk(fns []*Polynomial, from, to matrixOrder) ([]*Polynomial, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	basis := make([]*Polynomial, len(fns))
+	for i, f := range fns {
+		basis[i] = f.withOrder(from.TermOrder())
+	}
+	if !isGroebner(basis) {
+		return nil, errors.New("walk requires a Gröbner basis with respect to the start order")
+	}
+	basis = reduceBasis(basis)
+	// walking towards the target weight directly often ends with a
+	// degenerate step whose initial forms are as large as the basis, e.g.
+	// for the lexicographical order. Therefore the walk heads for a
+	// perturbed target weight first, which usually lies inside the cone of
+	// the final basis already.
+	perturbed := to.refine(to.perturb(basis))
+	basis = walkPath(basis, from[0], perturbed)
+	return walkPath(basis, perturbed[0], to), nil
+}
+
+// walkPath walks from the weight u, which is the first row of the order
+// of the basis, to the target order and returns the reduced Gröbner basis
+// with respect to the target order.
+func walkPath(basis []*Polynomial, u []*big.Rat, to matrixOrder) []*Polynomial {
+	v := to[0]
+	target := to.TermOrder()
+	cur, s := basis[0].order, new(big.Rat)
+	for {
+		var ok bool
+		if s, ok = nextWalkStep(basis, u, s, v, target); !ok {
+			break
+		}
+		w := make([]*big.Rat, len(u))
+		for i := range w {
+			// w = u + s*(v - u)
+			w[i] = new(big.Rat).Sub(v[i], u[i])
+			w[i].Mul(w[i], s)
+			w[i].Add(w[i], u[i])
+		}
+		next := to.refine(w).TermOrder()
+		basis = walkStep(basis, w, cur, next)
+		cur = next
+	}
+	// the leading terms are the same in the target order now
+	for i := range basis {
+		basis[i] = basis[i].withOrder(target)
+	}
+	return reduceBasis(basis)
+}
+
+// perturb combines the rows of m into a single weight vector, which
+// orders all terms of the basis like m if their degree is not too large:
+// w = sum N^(r-k) * m[k], where N exceeds the weights of all terms.
+func (m matrixOrder) perturb(basis []*Polynomial) []*big.Rat {
+	n := new(big.Rat)
+	for _, g := range basis {
+		for _, t := range g.items {
+			for _, row := range m {
+				x := weight(row, t.T)
+				if x.Abs(x).Cmp(n) > 0 {
+					n = x
+				}
+			}
+		}
+	}
+	n.Add(n, n).Add(n, big.NewRat(1, 1))
+	w := make([]*big.Rat, len(m[0]))
+	for j := range w {
+		w[j] = new(big.Rat)
+	}
+	for _, row := range m {
+		for j := range w {
+			w[j].Mul(w[j], n).Add(w[j], row[j])
+		}
+	}
+	return w
+}
+
+// nextWalkStep returns the smallest s >= prev for which the leading term of
+// some polynomial of the basis has the same weight with respect to w(s) as
+// another term, which is larger with respect to the target order.
+func nextWalkStep(basis []*Polynomial, u []*big.Rat, prev *big.Rat,
+	v []*big.Rat, target TermOrder) (*big.Rat, bool) {
+	var min *big.Rat
+	for _, g := range basis {
+		lt := g.items[0].T
+		du, dv := weight(u, lt), weight(v, lt)
+		for _, m := range g.items[1:] {
+			a := new(big.Rat).Sub(du, weight(u, m.T))
+			b := new(big.Rat).Sub(dv, weight(v, m.T))
+			var s *big.Rat
+			switch {
+			case b.Sign() < 0:
+				// (1-s)*a + s*b = 0
+				s = new(big.Rat).Quo(a, b.Sub(a, b))
+			case b.Sign() > 0 || !target(lt, m.T):
+				continue
+			case a.Sign() > 0:
+				// the tie at the target weight is broken by the target order
+				s = big.NewRat(1, 1)
+			default:
+				// both terms have the same weight along the whole path
+				s = prev
+			}
+			if min == nil || s.Cmp(min) < 0 {
+				min = s
+			}
+		}
+	}
+	return min, min != nil
+}
+
+// walkStep converts the Gröbner basis with respect to the order cur into a
+// Gröbner basis with respect to next, which is refined by the weight w.
+func walkStep(basis []*Polynomial, w []*big.Rat, cur, next TermOrder) []*Polynomial {
+	initial := make([]*Polynomial, len(basis))
+	for i, g := range basis {
+		initial[i] = g.initialForm(w)
+	}
+	inNext := make([]*Polynomial, len(initial))
+	for i := range initial {
+		inNext[i] = initial[i].withOrder(next)
+	}
+	rval := make([]*Polynomial, 0, len(basis))
+	for _, h := range Groebner(inNext) {
+		// lift h = sum q_i*in_w(g_i) to sum q_i*g_i
+		quo, _ := h.withOrder(cur).divide(initial)
+		f := &Polynomial{vars: h.vars, order: cur, field: h.field}
+		for i := range quo {
+			f = f.Add(quo[i].Mul(basis[i]))
+		}
+		rval = append(rval, f.withOrder(next))
+	}
+	return reduceBasis(rval)
+}
+
+// initialForm returns the sum of all monomials of p whose terms have the
+// maximal weight with respect to w.
+func (p *Polynomial) initialForm(w []*big.Rat) *Polynomial {
+	rval := &Polynomial{vars: p.vars, order: p.order, field: p.field}
+	var max *big.Rat
+	for _, m := range p.items {
+		x := weight(w, m.T)
+		if max == nil || x.Cmp(max) > 0 {
+			max = x
+			rval.items = rval.items[:0]
+		}
+		if x.Cmp(max) == 0 {
+			rval.items = append(rval.items, m)
+		}
+	}
+	return rval
+}